@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// how adjustment dates are written and entered
+const adjustmentDateFormat = "2006-01-02"
+
+// PointsAdjustment is points taken off a team, or given back, by the league: a deduction
+// for breaking the rules, say, or one cut on appeal
+type PointsAdjustment struct {
+	ID     int64 // database id, negative if it wasn't saved
+	Team   string
+	Week   int // counts in the table from the end of this week, 0 from the start of the season
+	Points int // negative for a deduction
+	Reason string
+	Date   time.Time // when it was handed down
+}
+
+// an id for an adjustment that isn't saved: negative, so it can't be taken for a
+// database id, and below any already handed out
+func (l *League) unsavedAdjustmentID() int64 {
+	id := int64(-1)
+	for _, a := range l.Adjustments {
+		if a.ID <= id {
+			id = a.ID - 1
+		}
+	}
+	return id
+}
+
+// "-10" or "+4"
+func (a PointsAdjustment) pointsText() string {
+	return fmt.Sprintf("%+d", a.Points)
+}
+
+// validate checks an adjustment can go into the league
+func (l *League) validateAdjustment(a PointsAdjustment) error {
+	if findTeam(l.Teams, a.Team) == nil {
+		return fmt.Errorf("%s isn't in the league", a.Team)
+	}
+	if a.Points == 0 || a.Points < -100 || a.Points > 100 {
+		return fmt.Errorf("points have to be between -100 and 100, and not 0")
+	}
+	if a.Week < 0 || a.Week > l.weeksPlayed() {
+		return fmt.Errorf("week has to be between 0 (before kick off) and %d, the last week played", l.weeksPlayed())
+	}
+	if strings.TrimSpace(a.Reason) == "" {
+		return fmt.Errorf("give a reason")
+	}
+	return nil
+}
+
+// add the adjustments that count from the end of week to the teams' points
+func (l *League) applyAdjustments(week int) {
+	for _, a := range l.Adjustments {
+		if a.Week == week {
+			if team := findTeam(l.Teams, a.Team); team != nil {
+				team.Points += a.Points
+				team.Adjustment += a.Points
+			}
+		}
+	}
+}
+
+// adjustments for weeks that aren't in the table any more, after an undo, still count
+func (l *League) applyLateAdjustments() {
+	for _, a := range l.Adjustments {
+		if a.Week > l.weeksPlayed() {
+			if team := findTeam(l.Teams, a.Team); team != nil {
+				team.Points += a.Points
+				team.Adjustment += a.Points
+			}
+		}
+	}
+}
+
+// the team's name with its adjustment for the table, "Everton (-10)"
+func (t *Team) annotatedName() string {
+	if t.Adjustment == 0 {
+		return t.Name
+	}
+	return fmt.Sprintf("%s (%+d)", t.Name, t.Adjustment)
+}
+
+// the notes under the table: one line per adjustment, blank if there aren't any
+func generateAdjustmentNotes(league *League) string {
+	var sb strings.Builder
+	for _, a := range league.Adjustments {
+		when := "before kick off"
+		if a.Week > 0 {
+			when = fmt.Sprintf("from week %d", a.Week)
+		}
+		sb.WriteString(fmt.Sprintf("%s %s pts: %s (%s, %s)\n", a.Team, a.pointsText(), a.Reason, a.Date.Format(adjustmentDateFormat), when))
+	}
+	return sb.String()
+}
+
+// showAdjustments opens the dialog for adding and removing points adjustments
+func (g *GUI) showAdjustments() {
+	var teamNames []string
+	var adjustments []PointsAdjustment
+	weeksPlayed := 0
+	g.engine.Read(func(league *League) {
+		for _, team := range league.Teams {
+			teamNames = append(teamNames, team.Name)
+		}
+		adjustments = append(adjustments, league.Adjustments...)
+		weeksPlayed = league.weeksPlayed()
+	})
+
+	statusLabel := widget.NewLabel("")
+	var dialog *widget.PopUp
+
+	// the adjustments already made, each with a button to take it off again
+	list := container.NewVBox()
+	if len(adjustments) == 0 {
+		list.Add(widget.NewLabel("No adjustments yet"))
+	}
+	for _, a := range adjustments {
+		id := a.ID
+		text := fmt.Sprintf("%-20s %4s  week %-2d %s  %s", a.Team, a.pointsText(), a.Week, a.Date.Format(adjustmentDateFormat), a.Reason)
+		label := widget.NewLabelWithStyle(text, fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+		list.Add(container.NewHBox(widget.NewButton("Remove", func() {
+			if err := g.engine.RemovePointsAdjustment(id); err != nil {
+				statusLabel.SetText(fmt.Sprintf("Failed to remove: %v", err))
+				return
+			}
+			dialog.Hide()
+			g.showAdjustments()
+		}), label))
+	}
+
+	teamSelect := widget.NewSelect(teamNames, nil)
+	teamSelect.PlaceHolder = "Team..."
+	pointsEntry := widget.NewEntry()
+	pointsEntry.SetPlaceHolder("-10")
+	weekEntry := widget.NewEntry()
+	weekEntry.SetText(strconv.Itoa(weeksPlayed))
+	dateEntry := widget.NewEntry()
+	dateEntry.SetText(time.Now().Format(adjustmentDateFormat))
+	reasonEntry := widget.NewEntry()
+	reasonEntry.SetPlaceHolder("Breach of financial rules")
+
+	form := container.NewGridWithColumns(3,
+		widget.NewLabel("Team:"), teamSelect, widget.NewLabel(""),
+		widget.NewLabel("Points:"), pointsEntry, widget.NewLabel("negative to take points off"),
+		widget.NewLabel("Week:"), weekEntry, widget.NewLabel(fmt.Sprintf("counts from the end of it, 0 - %d", weeksPlayed)),
+		widget.NewLabel("Date:"), dateEntry, widget.NewLabel("YYYY-MM-DD"),
+		widget.NewLabel("Reason:"), reasonEntry, widget.NewLabel(""),
+	)
+
+	addButton := widget.NewButton("Add", func() {
+		a := PointsAdjustment{Team: teamSelect.Selected, Reason: strings.TrimSpace(reasonEntry.Text)}
+		var err error
+		if a.Points, err = strconv.Atoi(strings.TrimSpace(pointsEntry.Text)); err != nil {
+			statusLabel.SetText("Points have to be a whole number")
+			return
+		}
+		if a.Week, err = strconv.Atoi(strings.TrimSpace(weekEntry.Text)); err != nil {
+			statusLabel.SetText("Week has to be a whole number")
+			return
+		}
+		if a.Date, err = time.Parse(adjustmentDateFormat, strings.TrimSpace(dateEntry.Text)); err != nil {
+			statusLabel.SetText("Date has to be YYYY-MM-DD")
+			return
+		}
+		if err := g.engine.AddPointsAdjustment(a); err != nil {
+			statusLabel.SetText(err.Error())
+			return
+		}
+		dialog.Hide()
+		g.showAdjustments()
+	})
+
+	scroll := container.NewVScroll(list)
+	scroll.SetMinSize(fyne.NewSize(640, 150))
+	content := container.NewVBox(
+		widget.NewLabelWithStyle("Points Adjustments", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		scroll,
+		widget.NewLabel(""),
+		form,
+		statusLabel,
+		container.NewHBox(addButton, widget.NewButton("Close", func() {
+			dialog.Hide()
+		})),
+	)
+	dialog = widget.NewModalPopUp(content, g.window.Canvas())
+	dialog.Resize(fyne.NewSize(700, 480))
+	dialog.Show()
+}
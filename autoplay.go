@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// range of the speed slider
+const (
+	minAutoPlaySpeed = 0.5
+	maxAutoPlaySpeed = 4.0
+)
+
+// autoPlay steps through the season a week at a time on a timer.
+// the timer fires on its own goroutine so everything here is behind the mutex
+type autoPlay struct {
+	mu        sync.Mutex
+	timer     *time.Timer // the pending next week, nil if there isn't one
+	running   bool
+	paused    bool
+	parked    bool    // paused with nothing scheduled, resume has to kick things off again
+	untilWeek int     // last week to play
+	speed     float64 // 1 is one week every play all delay in the settings
+	run       int     // bumped on every start and stop so stale timers know to give up
+}
+
+// is play all going, and which week is it heading for
+func (a *autoPlay) status() (running, paused bool, untilWeek int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.running, a.paused, a.untilWeek
+}
+
+// simulateToWeek plays weeks one after another until untilWeek has been played
+func (g *GUI) simulateToWeek(untilWeek int) {
+	a := g.autoPlay
+	a.mu.Lock()
+	if a.running {
+		a.mu.Unlock()
+		return
+	}
+	a.running = true
+	a.paused = false
+	a.parked = false
+	a.untilWeek = untilWeek
+	a.run++
+	run := a.run
+	a.mu.Unlock()
+
+	g.autoPlayStep(run)
+}
+
+// play the next week, then schedule the one after it
+func (g *GUI) autoPlayStep(run int) {
+	a := g.autoPlay
+	a.mu.Lock()
+	a.timer = nil
+	if run != a.run || !a.running {
+		a.mu.Unlock()
+		return
+	}
+	if a.paused {
+		a.parked = true
+		a.mu.Unlock()
+		return
+	}
+	untilWeek := a.untilWeek
+	a.mu.Unlock()
+
+	live := g.liveMode.Load()
+	g.livePlaying.Store(live)
+
+	week, ok := g.engine.SimulateWeekUpTo(untilWeek)
+	if !ok {
+		// reached the target week or the end of the season
+		g.livePlaying.Store(false)
+		g.finishAutoPlay(run)
+		return
+	}
+	last := week >= untilWeek
+
+	// in live mode wait for the matches to play out before moving on
+	if live {
+		g.playWeekLive(week, func() {
+			if last {
+				g.finishAutoPlay(run)
+			} else {
+				g.scheduleAutoPlay(run)
+			}
+			g.finishLiveWeek()
+		})
+		return
+	}
+
+	if last {
+		g.finishAutoPlay(run)
+		return
+	}
+	g.scheduleAutoPlay(run)
+}
+
+// set the timer for the next week, unless play all was stopped in the meantime
+func (g *GUI) scheduleAutoPlay(run int) {
+	a := g.autoPlay
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if run != a.run || !a.running {
+		return
+	}
+
+	delay := time.Duration(float64(g.engine.Settings().playAllDelay()) / a.speed)
+	a.timer = time.AfterFunc(delay, func() {
+		g.autoPlayStep(run)
+	})
+}
+
+// play all got where it was going
+func (g *GUI) finishAutoPlay(run int) {
+	a := g.autoPlay
+	a.mu.Lock()
+	if run == a.run {
+		a.running = false
+		a.paused = false
+		a.parked = false
+		a.run++
+	}
+	a.mu.Unlock()
+
+	// season is done - keep showAllResults false so user sees final week first
+	fyne.Do(func() {
+		if !g.livePlaying.Load() {
+			g.refreshDisplay()
+		}
+	})
+}
+
+// togglePause pauses play all after the current week, or picks it back up
+func (g *GUI) togglePause() {
+	a := g.autoPlay
+	a.mu.Lock()
+	if !a.running {
+		a.mu.Unlock()
+		return
+	}
+
+	a.paused = !a.paused
+	if a.paused && a.timer != nil {
+		// nothing has happened yet, so the week can just be picked up again on resume
+		a.timer.Stop()
+		a.timer = nil
+		a.parked = true
+	}
+	resume := !a.paused && a.parked
+	a.parked = false
+	run := a.run
+	a.mu.Unlock()
+
+	if resume {
+		g.autoPlayStep(run)
+	}
+}
+
+// stopAutoPlay cancels play all. the engine plays whole weeks under its lock,
+// so the league is always left at the end of a week
+func (g *GUI) stopAutoPlay() {
+	a := g.autoPlay
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.running {
+		return
+	}
+
+	if a.timer != nil {
+		a.timer.Stop()
+		a.timer = nil
+	}
+	a.running = false
+	a.paused = false
+	a.parked = false
+	a.run++
+}
+
+// change how quickly play all moves through the weeks, from the next week on
+func (g *GUI) setAutoPlaySpeed(speed float64) {
+	a := g.autoPlay
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.speed = speed
+}
+
+// autoPlayControls builds the pause, stop and speed controls.
+// onStop is called after stopping so the caller can tidy up its own view
+func (g *GUI) autoPlayControls(onStop func()) fyne.CanvasObject {
+	_, paused, untilWeek := g.autoPlay.status()
+
+	statusLabel := widget.NewLabel(fmt.Sprintf("Playing to week %d", untilWeek))
+	pauseButton := widget.NewButton("Pause", nil)
+	if paused {
+		pauseButton.SetText("Resume")
+		statusLabel.SetText(fmt.Sprintf("Paused, playing to week %d", untilWeek))
+	}
+	pauseButton.OnTapped = func() {
+		g.togglePause()
+		if _, paused, _ := g.autoPlay.status(); paused {
+			pauseButton.SetText("Resume")
+			statusLabel.SetText(fmt.Sprintf("Paused, playing to week %d", untilWeek))
+		} else {
+			pauseButton.SetText("Pause")
+			statusLabel.SetText(fmt.Sprintf("Playing to week %d", untilWeek))
+		}
+	}
+
+	stopButton := widget.NewButton("Stop", func() {
+		g.stopAutoPlay()
+		onStop()
+	})
+
+	g.autoPlay.mu.Lock()
+	speed := g.autoPlay.speed
+	g.autoPlay.mu.Unlock()
+
+	speedLabel := widget.NewLabel(fmt.Sprintf("Speed %.1fx", speed))
+	speedSlider := widget.NewSlider(minAutoPlaySpeed, maxAutoPlaySpeed)
+	speedSlider.Step = 0.5
+	speedSlider.SetValue(speed)
+	speedSlider.OnChanged = func(value float64) {
+		g.setAutoPlaySpeed(value)
+		speedLabel.SetText(fmt.Sprintf("Speed %.1fx", value))
+	}
+	sliderBox := container.NewGridWrap(fyne.NewSize(160, speedSlider.MinSize().Height), speedSlider)
+
+	return container.NewHBox(
+		statusLabel,
+		widget.NewLabel("  "),
+		pauseButton,
+		stopButton,
+		widget.NewLabel("  "),
+		speedLabel,
+		sliderBox,
+	)
+}
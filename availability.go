@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// suspension rules
+const (
+	yellowCardLimit  = 5 // every 5th yellow means a one match ban
+	yellowCardBan    = 1
+	redCardBan       = 1
+	injuryChance     = 0.12 // chance a team picks up an injury in a match
+	redCardChance    = 0.03 // chance a team has someone sent off
+	yellowCardChance = 0.13 // chance each starter gets booked
+)
+
+// the starting eleven we measure a team's strength by
+var startingFormation = []struct {
+	position string
+	count    int
+}{
+	{Goalkeeper, 1},
+	{Defender, 4},
+	{Midfielder, 4},
+	{Forward, 2},
+}
+
+// a booking or sending off in a match
+type Card struct {
+	Team   *Team
+	Player *Player
+	Red    bool
+}
+
+// an injury picked up in a match
+type Injury struct {
+	Team   *Team
+	Player *Player
+	Weeks  int // how many weeks they'll miss
+}
+
+// is the player fit and not suspended for the given week
+func (p *Player) isAvailable(week int) bool {
+	return p.UnavailableUntil < week
+}
+
+// players that can be picked for the given week
+func (t *Team) availableSquad(week int) []*Player {
+	var available []*Player
+	for _, p := range t.Squad {
+		if p.isAvailable(week) {
+			available = append(available, p)
+		}
+	}
+	return available
+}
+
+// pick the strongest eleven from the given players. anyone filling a gap
+// in the formation out of position is returned separately
+func startingEleven(players []*Player) ([]*Player, []*Player) {
+	byPosition := make(map[string][]*Player)
+	for _, p := range players {
+		byPosition[p.Position] = append(byPosition[p.Position], p)
+	}
+
+	picked := make(map[*Player]bool)
+	var eleven []*Player
+	missing := 0
+	for _, slot := range startingFormation {
+		candidates := byPosition[slot.position]
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].Rating > candidates[j].Rating
+		})
+		for i := 0; i < slot.count; i++ {
+			if i < len(candidates) {
+				picked[candidates[i]] = true
+				eleven = append(eleven, candidates[i])
+			} else {
+				missing++
+			}
+		}
+	}
+
+	// fill any gaps with whoever is left
+	var outOfPosition []*Player
+	for _, p := range players {
+		if len(outOfPosition) == missing {
+			break
+		}
+		if !picked[p] && p.Position != Goalkeeper {
+			picked[p] = true
+			outOfPosition = append(outOfPosition, p)
+		}
+	}
+	return eleven, outOfPosition
+}
+
+// average rating of the best eleven we can put out from the given players
+func bestElevenRating(players []*Player) float64 {
+	eleven, outOfPosition := startingEleven(players)
+
+	total := 0
+	for _, p := range eleven {
+		total += p.Rating
+	}
+	// playing out of position costs them
+	for _, p := range outOfPosition {
+		total += p.Rating - 10
+	}
+
+	// anyone we still couldn't fill counts as a zero
+	return float64(total) / 11.0
+}
+
+// how strong the team is with its absentees compared to a full squad
+func (t *Team) availabilityFactor(week int) float64 {
+	if len(t.Squad) == 0 {
+		return 1.0
+	}
+	full := bestElevenRating(t.Squad)
+	if full == 0 {
+		return 1.0
+	}
+	return bestElevenRating(t.availableSquad(week)) / full
+}
+
+// apply a played match's cards and injuries to the players involved
+func (m *Match) applyIncidents() {
+	for _, card := range m.Cards {
+		p := card.Player
+		if card.Red {
+			p.Reds++
+			p.suspend(m.dueWeek() + redCardBan)
+			continue
+		}
+		p.Yellows++
+		if p.Yellows%yellowCardLimit == 0 {
+			p.suspend(m.dueWeek() + yellowCardBan)
+		}
+	}
+
+	for _, injury := range m.Injuries {
+		p := injury.Player
+		if m.dueWeek()+injury.Weeks > p.UnavailableUntil {
+			p.UnavailableUntil = m.dueWeek() + injury.Weeks
+			p.Absence = "Injured"
+		}
+	}
+}
+
+// rule a player out until the end of the given week
+func (p *Player) suspend(untilWeek int) {
+	if untilWeek > p.UnavailableUntil {
+		p.UnavailableUntil = untilWeek
+		p.Absence = "Suspended"
+	}
+}
+
+// generateAvailabilityReport lists who is missing for the upcoming week
+func (g *GUI) generateAvailabilityReport(league *League) string {
+	week := league.upcomingWeek()
+	if week > len(league.Fixtures) {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Team News (Week %d)\n", week))
+	sb.WriteString("----------------------------------------\n")
+	for _, team := range league.Teams {
+		var out []string
+		for _, p := range team.Squad {
+			if !p.isAvailable(week) {
+				out = append(out, fmt.Sprintf("%s (%s, %s until week %d)",
+					p.Name, p.Position, strings.ToLower(p.Absence), p.UnavailableUntil))
+			}
+		}
+
+		sb.WriteString(fmt.Sprintf("%-20s %3.0f%% strength\n", team.Name, team.availabilityFactor(week)*100))
+		if len(out) == 0 {
+			sb.WriteString("    Full squad available\n")
+		}
+		for _, line := range out {
+			sb.WriteString("    " + line + "\n")
+		}
+	}
+	return sb.String()
+}
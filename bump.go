@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// WeekStandings is the league table as it stood after a week
+type WeekStandings struct {
+	Week      int
+	Positions map[string]int // 1 for top of the table
+	Points    map[string]int
+	Strength  map[string]int // strength from form going into the next week
+}
+
+// the table as the teams' stats stand right now, labelled as week
+func (l *League) standingsAfter(week int) WeekStandings {
+	teams := make([]*Team, len(l.Teams))
+	copy(teams, l.Teams)
+	sortTeams(teams)
+
+	standings := WeekStandings{
+		Week:      week,
+		Positions: make(map[string]int),
+		Points:    make(map[string]int),
+		Strength:  make(map[string]int),
+	}
+	for i, team := range teams {
+		standings.Positions[team.Name] = i + 1
+		standings.Points[team.Name] = team.Points
+		standings.Strength[team.Name] = team.CurrentStrength
+	}
+	return standings
+}
+
+// team names in alphabetical order, so a team keeps its colour from chart to chart
+func (l *League) sortedTeamNames() []string {
+	var names []string
+	for _, team := range l.Teams {
+		names = append(names, team.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// bumpChart draws every team's league position week by week
+func (l *League) bumpChart() *lineChart {
+	chart := &lineChart{
+		Title:    "League Position",
+		XLabel:   "Week",
+		XMin:     1,
+		XMax:     len(l.Fixtures),
+		YMin:     1,
+		YMax:     float64(len(l.Teams)),
+		YStep:    1,
+		Inverted: true,
+	}
+
+	for i, name := range l.sortedTeamNames() {
+		series := chartSeries{Name: name, Color: chartColors[i%len(chartColors)]}
+		for _, standings := range l.PositionHistory {
+			series.Points = append(series.Points, chartPoint{X: standings.Week, Y: float64(standings.Positions[name])})
+		}
+		chart.Series = append(chart.Series, series)
+	}
+	return chart
+}
+
+// showBumpChart opens the chart of league positions over the season
+func (g *GUI) showBumpChart() {
+	var chart *lineChart
+	var weeks int
+	g.engine.Read(func(league *League) {
+		chart = league.bumpChart()
+		weeks = len(league.PositionHistory)
+	})
+
+	content := container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("League position after each of the %d weeks played", weeks)),
+		chart.canvasObject(chartWidth, chartHeight),
+		g.chartExportRow(func() *lineChart { return chart }, "positions.svg"),
+	)
+	dialog := widget.NewModalPopUp(content, g.window.Canvas())
+	dialog.Content = container.NewVBox(content, widget.NewButton("Close", func() {
+		dialog.Hide()
+	}))
+	dialog.Resize(fyne.NewSize(chartWidth+40, chartHeight+200))
+	dialog.Show()
+}
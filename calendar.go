@@ -0,0 +1,485 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// how kickoffs are saved, and round dates
+const (
+	kickoffFormat   = "2006-01-02 15:04"
+	roundDateFormat = "2006-01-02"
+)
+
+// CalendarConfig is when the season's weeks are played. dates are month and day, "09-01",
+// so the same config works for every season
+type CalendarConfig struct {
+	Start           string           `toml:"start" yaml:"start"`                       // the first round is the saturday on or after this
+	MidweekRounds   []int            `toml:"midweek_rounds" yaml:"midweek_rounds"`     // weeks played on tuesday and wednesday nights, not at the weekend
+	WeekendKickoffs []string         `toml:"weekend_kickoffs" yaml:"weekend_kickoffs"` // "Sat 15:00", fri to mon, handed out to a week's matches in turn
+	MidweekKickoffs []string         `toml:"midweek_kickoffs" yaml:"midweek_kickoffs"` // "Tue 19:45", tue to thu
+	Blackouts       []BlackoutConfig `toml:"blackouts" yaml:"blackouts"`               // no round is played in these, international breaks say
+}
+
+// BlackoutConfig is a stretch of the season with no league games
+type BlackoutConfig struct {
+	Name string `toml:"name" yaml:"name"`
+	From string `toml:"from" yaml:"from"` // month and day, "09-01"
+	To   string `toml:"to" yaml:"to"`     // the last day of it, can be in the next year: "12-29" to "01-04"
+}
+
+// Round is when a week is played: the saturday, or the tuesday for a midweek round
+type Round struct {
+	Date    time.Time
+	Midweek bool
+}
+
+// a kickoff slot, days from the round's saturday (or tuesday) and the time of day
+type kickoffSlot struct {
+	days   int
+	hour   int
+	minute int
+}
+
+// the calendar new leagues start with
+func builtinCalendar() CalendarConfig {
+	return CalendarConfig{
+		Start:           "08-09",
+		MidweekRounds:   []int{4, 12},
+		WeekendKickoffs: []string{"Sat 12:30", "Sat 15:00", "Sat 17:30", "Sun 14:00", "Sun 16:30"},
+		MidweekKickoffs: []string{"Tue 19:45", "Wed 20:00"},
+		Blackouts: []BlackoutConfig{
+			{Name: "International break", From: "09-01", To: "09-09"},
+			{Name: "International break", From: "10-06", To: "10-14"},
+			{Name: "International break", From: "11-10", To: "11-18"},
+		},
+	}
+}
+
+// parse "Sat 15:00". days are counted from saturday, or tuesday for a midweek kickoff
+func parseKickoff(s string, midweek bool) (kickoffSlot, error) {
+	days := map[string]int{"fri": -1, "sat": 0, "sun": 1, "mon": 2}
+	if midweek {
+		days = map[string]int{"tue": 0, "wed": 1, "thu": 2}
+	}
+	day, clock, ok := strings.Cut(strings.TrimSpace(s), " ")
+	offset, known := days[strings.ToLower(day)]
+	if !ok || !known {
+		if midweek {
+			return kickoffSlot{}, fmt.Errorf("midweek kickoff %q has to be Tue, Wed or Thu and a time, like Tue 19:45", s)
+		}
+		return kickoffSlot{}, fmt.Errorf("weekend kickoff %q has to be Fri, Sat, Sun or Mon and a time, like Sat 15:00", s)
+	}
+	t, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return kickoffSlot{}, fmt.Errorf("kickoff %q: time has to be hh:mm", s)
+	}
+	return kickoffSlot{days: offset, hour: t.Hour(), minute: t.Minute()}, nil
+}
+
+// parse every kickoff of one kind
+func parseKickoffs(list []string, midweek bool) ([]kickoffSlot, error) {
+	var slots []kickoffSlot
+	for _, s := range list {
+		slot, err := parseKickoff(s, midweek)
+		if err != nil {
+			return nil, err
+		}
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
+
+// the date a month and day ("09-01") falls on in year
+func monthDay(s string, year int) (time.Time, error) {
+	t, err := time.Parse("01-02", strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q has to be a month and day, like 09-01", s)
+	}
+	return time.Date(year, t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// validate checks the calendar can be used to set dates
+func (c CalendarConfig) validate() error {
+	if _, err := monthDay(c.Start, 2000); err != nil {
+		return fmt.Errorf("calendar start: %v", err)
+	}
+	for _, week := range c.MidweekRounds {
+		if week < 1 {
+			return fmt.Errorf("calendar midweek_rounds: weeks start at 1")
+		}
+	}
+	if len(c.WeekendKickoffs) == 0 || len(c.MidweekKickoffs) == 0 {
+		return fmt.Errorf("calendar needs at least one weekend and one midweek kickoff")
+	}
+	if _, err := parseKickoffs(c.WeekendKickoffs, false); err != nil {
+		return fmt.Errorf("calendar: %v", err)
+	}
+	if _, err := parseKickoffs(c.MidweekKickoffs, true); err != nil {
+		return fmt.Errorf("calendar: %v", err)
+	}
+	for _, b := range c.Blackouts {
+		if _, err := monthDay(b.From, 2000); err != nil {
+			return fmt.Errorf("calendar blackout %s: %v", b.Name, err)
+		}
+		if _, err := monthDay(b.To, 2000); err != nil {
+			return fmt.Errorf("calendar blackout %s: %v", b.Name, err)
+		}
+	}
+	return nil
+}
+
+// the year a season starts in: "2025" and "2025/26" are 2025, anything else is this year
+func seasonYear(season string) int {
+	if len(season) >= 4 {
+		if year, err := strconv.Atoi(season[:4]); err == nil {
+			return year
+		}
+	}
+	return time.Now().Year()
+}
+
+// the blackout date falls in, "" if it's free. blackouts are checked in the
+// year before as well, for one running over new year
+func (c CalendarConfig) blackout(date time.Time) string {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	for _, b := range c.Blackouts {
+		for _, year := range []int{date.Year() - 1, date.Year()} {
+			from, _ := monthDay(b.From, year)
+			to, _ := monthDay(b.To, year)
+			if to.Before(from) {
+				to = to.AddDate(1, 0, 0)
+			}
+			if !date.Before(from) && !date.After(to) {
+				return b.Name
+			}
+		}
+	}
+	return ""
+}
+
+// is week played midweek
+func (c CalendarConfig) midweekRound(week int) bool {
+	for _, w := range c.MidweekRounds {
+		if w == week {
+			return true
+		}
+	}
+	return false
+}
+
+// the date of every week in a season: saturdays, tuesday nights for midweek rounds,
+// and nothing in a blackout
+func (c CalendarConfig) roundDates(season string, weeks int) ([]Round, error) {
+	start, err := monthDay(c.Start, seasonYear(season))
+	if err != nil {
+		return nil, err
+	}
+	var rounds []Round
+	// the day before the first saturday, so it's the first one tried
+	previous := start.AddDate(0, 0, int(time.Saturday-start.Weekday()+7)%7-1)
+	for week := 1; week <= weeks; week++ {
+		round := Round{Midweek: c.midweekRound(week)}
+		target := time.Saturday
+		if round.Midweek {
+			target = time.Tuesday
+		}
+		round.Date = previous.AddDate(0, 0, int(target-previous.Weekday()+7)%7)
+		if !round.Date.After(previous) {
+			round.Date = round.Date.AddDate(0, 0, 7)
+		}
+		for tries := 0; c.blackout(round.Date) != ""; tries++ {
+			if tries > 52 {
+				return nil, fmt.Errorf("the blackouts leave no dates for week %d", week)
+			}
+			round.Date = round.Date.AddDate(0, 0, 7)
+		}
+		rounds = append(rounds, round)
+		previous = round.Date
+	}
+	return rounds, nil
+}
+
+// a kickoff in a round
+func (r Round) kickoff(slot kickoffSlot) time.Time {
+	return r.Date.AddDate(0, 0, slot.days).Add(time.Duration(slot.hour)*time.Hour + time.Duration(slot.minute)*time.Minute)
+}
+
+// scheduleDates gives every week a date and every match a kickoff, handing the
+// round's kickoff slots out in turn
+func (l *League) scheduleDates(c CalendarConfig) error {
+	rounds, err := c.roundDates(l.Season, len(l.Fixtures))
+	if err != nil {
+		return err
+	}
+	weekend, err := parseKickoffs(c.WeekendKickoffs, false)
+	if err != nil {
+		return err
+	}
+	midweek, err := parseKickoffs(c.MidweekKickoffs, true)
+	if err != nil {
+		return err
+	}
+	l.Rounds = rounds
+	l.Calendar = c
+	for w, round := range rounds {
+		slots := weekend
+		if round.Midweek {
+			slots = midweek
+		}
+		for i := range l.Fixtures[w] {
+			l.Fixtures[w][i].Kickoff = round.kickoff(slots[i%len(slots)])
+		}
+	}
+	return nil
+}
+
+// the kickoff for a match rearranged to week, or the midweek before it, from the calendar
+// the league's dates came from. a weekend round's midweek is the tuesday before, at the
+// first midweek kickoff out of the blackouts. after a midweek round there's no midweek
+// before the weekend, it would be the same night. zero if the league has no dates
+func (l *League) slotKickoff(week int, midweek bool) (time.Time, error) {
+	if week < 1 || week > len(l.Rounds) {
+		return time.Time{}, nil
+	}
+	round := l.Rounds[week-1]
+	list := l.Calendar.WeekendKickoffs
+	if round.Midweek || midweek {
+		list = l.Calendar.MidweekKickoffs
+	}
+	slots, err := parseKickoffs(list, round.Midweek || midweek)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if midweek && !round.Midweek {
+		if week > 1 && l.Rounds[week-2].Midweek {
+			return time.Time{}, fmt.Errorf("week %d comes straight after a midweek round, there's no midweek before it", week)
+		}
+		round = Round{Date: round.Date.AddDate(0, 0, -4), Midweek: true}
+	}
+	for _, slot := range slots {
+		if kickoff := round.kickoff(slot); l.Calendar.blackout(kickoff) == "" {
+			return kickoff, nil
+		}
+	}
+	slot := "week"
+	if midweek {
+		slot = "midweek"
+	}
+	return time.Time{}, fmt.Errorf("week %d's %s falls in a break", week, slot)
+}
+
+// the date week is played on, "Sat 16 Aug", blank if the league has no dates
+func (l *League) weekDate(week int) string {
+	if week < 1 || week > len(l.Rounds) {
+		return ""
+	}
+	return l.Rounds[week-1].Date.Format("Mon 2 Jan")
+}
+
+// "Sat 15:00", blank if the match has no kickoff
+func (m *Match) kickoffText() string {
+	if m.Kickoff.IsZero() {
+		return ""
+	}
+	return m.Kickoff.Format("Mon 15:04")
+}
+
+// the day of a match, "Sat 16 Aug", blank if it has none
+func (m *Match) dayText() string {
+	if m.Kickoff.IsZero() {
+		return ""
+	}
+	return m.Kickoff.Format("Mon 2 Jan")
+}
+
+// a match's date for lists, "Sat 16 Aug 15:00", blank if it has none
+func (m *Match) dateText() string {
+	if m.Kickoff.IsZero() {
+		return ""
+	}
+	return m.Kickoff.Format("Mon 2 Jan 15:04")
+}
+
+// the days matches are played on, in order
+func (l *League) matchDays() []time.Time {
+	seen := make(map[time.Time]bool)
+	var days []time.Time
+	for w := range l.Fixtures {
+		for _, match := range l.Fixtures[w] {
+			if match.Kickoff.IsZero() {
+				continue
+			}
+			day := time.Date(match.Kickoff.Year(), match.Kickoff.Month(), match.Kickoff.Day(), 0, 0, 0, 0, time.UTC)
+			if !seen[day] {
+				seen[day] = true
+				days = append(days, day)
+			}
+		}
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Before(days[j])
+	})
+	return days
+}
+
+// the matches on a day, by kickoff
+func (l *League) dayMatches(day time.Time) []*Match {
+	var matches []*Match
+	for w := range l.Fixtures {
+		for i := range l.Fixtures[w] {
+			match := &l.Fixtures[w][i]
+			if y, m, d := match.Kickoff.Date(); y == day.Year() && m == day.Month() && d == day.Day() {
+				matches = append(matches, match)
+			}
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Kickoff.Before(matches[j].Kickoff)
+	})
+	return matches
+}
+
+// showCalendar opens the fixtures and results day by day. days can be stepped through,
+// picked from the list or jumped to by date, and the season played up to one
+func (g *GUI) showCalendar() {
+	var days []time.Time
+	var calendar CalendarConfig
+	g.engine.Read(func(league *League) {
+		days = league.matchDays()
+		calendar = league.Calendar
+	})
+	locked := g.historyLocked()
+	if len(days) == 0 {
+		return
+	}
+
+	var dayOptions []string
+	for _, day := range days {
+		dayOptions = append(dayOptions, day.Format("Mon 2 Jan 2006"))
+	}
+
+	var dialog *widget.PopUp
+	dayList := container.NewVBox()
+	breakLabel := widget.NewLabel("")
+	statusLabel := widget.NewLabel("")
+	var daySelect *widget.Select
+	current := 0
+
+	// fill in a day's matches: results open their report, fixtures can be played up to
+	showDay := func(index int) {
+		current = index
+		day := days[index]
+		dayList.RemoveAll()
+		lastWeek := 0
+		g.engine.Read(func(league *League) {
+			for _, m := range league.dayMatches(day) {
+				match := *m
+				lastWeek = max(lastWeek, match.dueWeek())
+				if match.IsPlayed {
+					text := fmt.Sprintf("%s  %s %d - %d %s%s%s", match.kickoffText(), match.HomeTeam.Name, match.HomeGoals,
+						match.AwayGoals, match.AwayTeam.Name, match.penaltiesNote(), match.statusNote())
+					dayList.Add(widget.NewButton(text, func() {
+						dialog.Hide()
+						g.showMatchReport(match)
+					}))
+					continue
+				}
+				text := fmt.Sprintf("%s  %s v %s  (week %d)%s", match.kickoffText(), match.HomeTeam.Name, match.AwayTeam.Name,
+					match.dueWeek(), match.statusNote())
+				dayList.Add(widget.NewLabelWithStyle(text, fyne.TextAlignLeading, fyne.TextStyle{Monospace: true}))
+			}
+			if lastWeek >= league.upcomingWeek() && !locked {
+				dayList.Add(widget.NewButton(fmt.Sprintf("Play up to this day (week %d)", lastWeek), func() {
+					dialog.Hide()
+					g.simulateToWeek(lastWeek)
+				}))
+			}
+		})
+		breakLabel.SetText("")
+		// say if the next match day is after a break
+		if index+1 < len(days) {
+			for d := day.AddDate(0, 0, 1); d.Before(days[index+1]); d = d.AddDate(0, 0, 1) {
+				if name := calendar.blackout(d); name != "" {
+					breakLabel.SetText(fmt.Sprintf("Next: %s, back on %s", name, days[index+1].Format("Mon 2 Jan")))
+					break
+				}
+			}
+		}
+		dayList.Refresh()
+		if daySelect.SelectedIndex() != index {
+			daySelect.SetSelectedIndex(index)
+		}
+	}
+
+	daySelect = widget.NewSelect(dayOptions, func(string) {
+		if i := daySelect.SelectedIndex(); i >= 0 && i != current {
+			showDay(i)
+		}
+	})
+	prevButton := widget.NewButton("◀ Prev", func() {
+		if current > 0 {
+			showDay(current - 1)
+		}
+	})
+	nextButton := widget.NewButton("Next ▶", func() {
+		if current < len(days)-1 {
+			showDay(current + 1)
+		}
+	})
+
+	// jump to the first match day on or after a date
+	dateEntry := widget.NewEntry()
+	dateEntry.SetPlaceHolder(roundDateFormat)
+	goButton := widget.NewButton("Go", func() {
+		date, err := time.Parse(roundDateFormat, strings.TrimSpace(dateEntry.Text))
+		if err != nil {
+			statusLabel.SetText("Date has to be YYYY-MM-DD")
+			return
+		}
+		statusLabel.SetText("")
+		for i, day := range days {
+			if !day.Before(date) {
+				showDay(i)
+				return
+			}
+		}
+		statusLabel.SetText("No matches on or after " + date.Format("2 Jan 2006"))
+	})
+
+	// start on the next day there's something to play, or the last day of the season
+	start := len(days) - 1
+	g.engine.Read(func(league *League) {
+		for i, day := range days {
+			for _, match := range league.dayMatches(day) {
+				if !match.IsPlayed && i < start {
+					start = i
+				}
+			}
+		}
+	})
+
+	scroll := container.NewVScroll(dayList)
+	scroll.SetMinSize(fyne.NewSize(560, 260))
+	content := container.NewVBox(
+		widget.NewLabelWithStyle("Calendar", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewHBox(prevButton, daySelect, nextButton, widget.NewLabel("  "), dateEntry, goButton),
+		scroll,
+		breakLabel,
+		statusLabel,
+		widget.NewButton("Close", func() {
+			dialog.Hide()
+		}),
+	)
+	dialog = widget.NewModalPopUp(content, g.window.Canvas())
+	dialog.Resize(fyne.NewSize(620, 440))
+	showDay(start)
+	dialog.Show()
+}
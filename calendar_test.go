@@ -0,0 +1,105 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRoundDates(t *testing.T) {
+	rounds, err := builtinCalendar().roundDates("2025/26", 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rounds) != 15 {
+		t.Fatalf("got %d rounds, want 15", len(rounds))
+	}
+
+	tests := []struct {
+		week    int
+		date    string
+		midweek bool
+	}{
+		{1, "2025-08-09", false}, // the start is a saturday
+		{2, "2025-08-16", false},
+		{3, "2025-08-23", false},
+		{4, "2025-08-26", true}, // tuesday after week 3
+		{5, "2025-08-30", false},
+		{6, "2025-09-13", false}, // after the september break
+		{9, "2025-10-04", false},
+		{10, "2025-10-18", false}, // after the october break
+		{11, "2025-10-25", false},
+		{12, "2025-10-28", true},
+		{13, "2025-11-01", false},
+		{14, "2025-11-08", false},
+		{15, "2025-11-22", false}, // after the november break
+	}
+	for _, tt := range tests {
+		round := rounds[tt.week-1]
+		if got := round.Date.Format(roundDateFormat); got != tt.date || round.Midweek != tt.midweek {
+			t.Errorf("week %d: %s (midweek %v), want %s (midweek %v)", tt.week, got, round.Midweek, tt.date, tt.midweek)
+		}
+	}
+}
+
+func TestRoundDatesStart(t *testing.T) {
+	tests := []struct {
+		name   string
+		season string
+		start  string
+		want   string
+	}{
+		{"saturday start", "2025", "08-09", "2025-08-09"},
+		{"midweek start moves to the saturday", "2025", "08-06", "2025-08-09"},
+		{"sunday start moves to the next saturday", "2025", "08-10", "2025-08-16"},
+		{"season with the year after", "2026/27", "08-01", "2026-08-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := builtinCalendar()
+			c.Start = tt.start
+			rounds, err := c.roundDates(tt.season, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rounds[0].Date.Format(roundDateFormat); got != tt.want {
+				t.Errorf("week 1 is %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundDatesBlackedOut(t *testing.T) {
+	c := builtinCalendar()
+	c.Blackouts = []BlackoutConfig{{Name: "Strike", From: "01-01", To: "12-31"}}
+	if _, err := c.roundDates("2025", 38); err == nil {
+		t.Error("no error with the whole year blacked out")
+	}
+}
+
+func TestBlackout(t *testing.T) {
+	c := CalendarConfig{Blackouts: []BlackoutConfig{
+		{Name: "International break", From: "09-01", To: "09-09"},
+		{Name: "Winter break", From: "12-29", To: "01-04"},
+	}}
+	tests := []struct {
+		date string
+		want string
+	}{
+		{"2025-08-31", ""},
+		{"2025-09-01", "International break"},
+		{"2025-09-09", "International break"},
+		{"2025-09-10", ""},
+		{"2025-12-28", ""},
+		{"2025-12-29", "Winter break"},
+		{"2026-01-02", "Winter break"}, // carried over from the year before
+		{"2026-01-04", "Winter break"},
+		{"2026-01-05", ""},
+	}
+	for _, tt := range tests {
+		date, _ := time.Parse(roundDateFormat, tt.date)
+		// a kickoff late on the last day is still in it
+		if got := c.blackout(date.Add(20 * time.Hour)); got != tt.want {
+			t.Errorf("blackout(%s) = %q, want %q", tt.date, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// size charts are drawn and exported at
+const (
+	chartWidth  = 760
+	chartHeight = 420
+)
+
+// space around the plot for the title, axis labels and legend
+const (
+	chartMarginLeft   = 50
+	chartMarginRight  = 180
+	chartMarginTop    = 40
+	chartMarginBottom = 45
+)
+
+// colours given to the lines in turn
+var chartColors = []color.NRGBA{
+	{31, 119, 180, 255},
+	{214, 39, 40, 255},
+	{44, 160, 44, 255},
+	{255, 127, 14, 255},
+	{148, 103, 189, 255},
+	{140, 86, 75, 255},
+	{227, 119, 194, 255},
+	{23, 190, 207, 255},
+}
+
+var (
+	chartBackground = color.NRGBA{255, 255, 255, 255}
+	chartAxisColor  = color.NRGBA{60, 60, 60, 255}
+	chartGridColor  = color.NRGBA{225, 225, 225, 255}
+)
+
+// lineChart is a chart of values week by week, one line per team
+type lineChart struct {
+	Title    string
+	XLabel   string
+	XMin     int // first and last week on the x axis
+	XMax     int
+	YMin     float64
+	YMax     float64
+	YStep    float64 // gap between labels on the y axis
+	Inverted bool    // lowest values at the top, for positions
+	Series   []chartSeries
+}
+
+// one line on a chart
+type chartSeries struct {
+	Name   string
+	Color  color.NRGBA
+	Points []chartPoint
+}
+
+type chartPoint struct {
+	X int
+	Y float64
+}
+
+// the chart broken down into lines and text, so the gui, svg and png
+// all draw exactly the same thing
+type chartLine struct {
+	x1, y1, x2, y2 float64
+	color          color.NRGBA
+	width          float64
+}
+
+type chartText struct {
+	x, y  float64 // y is the baseline
+	text  string
+	color color.NRGBA
+	align int // -1 left, 0 centre, 1 right of x
+	bold  bool
+}
+
+// where a value goes on a chart w by h pixels
+func (c *lineChart) toPixel(x int, y float64, w, h float64) (float64, float64) {
+	plotWidth := w - chartMarginLeft - chartMarginRight
+	plotHeight := h - chartMarginTop - chartMarginBottom
+
+	xRange := math.Max(float64(c.XMax-c.XMin), 1)
+	px := chartMarginLeft + float64(x-c.XMin)/xRange*plotWidth
+
+	frac := 0.0
+	if c.YMax > c.YMin {
+		frac = (y - c.YMin) / (c.YMax - c.YMin)
+	}
+	if c.Inverted {
+		frac = 1 - frac
+	}
+	py := chartMarginTop + (1-frac)*plotHeight
+	return px, py
+}
+
+// lay the chart out for w by h pixels
+func (c *lineChart) layout(w, h float64) ([]chartLine, []chartText) {
+	var lines []chartLine
+	var texts []chartText
+	left, top := float64(chartMarginLeft), float64(chartMarginTop)
+	right, bottom := w-chartMarginRight, h-chartMarginBottom
+
+	texts = append(texts, chartText{x: (left + right) / 2, y: top - 16, text: c.Title, color: chartAxisColor, bold: true})
+
+	// grid and labels up the side
+	if c.YStep > 0 {
+		for y := c.YMin; y <= c.YMax+c.YStep/1000; y += c.YStep {
+			_, py := c.toPixel(c.XMin, y, w, h)
+			lines = append(lines, chartLine{left, py, right, py, chartGridColor, 1})
+			texts = append(texts, chartText{x: left - 6, y: py + 4, text: fmt.Sprintf("%g", y), color: chartAxisColor, align: 1})
+		}
+	}
+
+	// weeks along the bottom, thinned out if there are lots of them
+	xStep := 1
+	for (c.XMax-c.XMin)/xStep > 20 {
+		xStep++
+	}
+	for x := c.XMin; x <= c.XMax; x += xStep {
+		px, _ := c.toPixel(x, c.YMin, w, h)
+		lines = append(lines, chartLine{px, bottom, px, bottom + 4, chartAxisColor, 1})
+		texts = append(texts, chartText{x: px, y: bottom + 17, text: fmt.Sprintf("%d", x), color: chartAxisColor})
+	}
+	texts = append(texts, chartText{x: (left + right) / 2, y: h - 8, text: c.XLabel, color: chartAxisColor})
+
+	lines = append(lines,
+		chartLine{left, top, left, bottom, chartAxisColor, 1},
+		chartLine{left, bottom, right, bottom, chartAxisColor, 1},
+	)
+
+	// the lines themselves, with a small mark on each week
+	for i, series := range c.Series {
+		for j, p := range series.Points {
+			px, py := c.toPixel(p.X, p.Y, w, h)
+			lines = append(lines, chartLine{px - 2, py, px + 2, py, series.Color, 4})
+			if j > 0 {
+				prevX, prevY := c.toPixel(series.Points[j-1].X, series.Points[j-1].Y, w, h)
+				lines = append(lines, chartLine{prevX, prevY, px, py, series.Color, 2})
+			}
+		}
+
+		// legend down the right
+		ly := top + 10 + float64(i)*20
+		lines = append(lines, chartLine{right + 15, ly - 4, right + 35, ly - 4, series.Color, 3})
+		texts = append(texts, chartText{x: right + 42, y: ly, text: series.Name, color: chartAxisColor, align: -1})
+	}
+	return lines, texts
+}
+
+// canvasObject draws the chart for the gui
+func (c *lineChart) canvasObject(w, h float32) fyne.CanvasObject {
+	lines, texts := c.layout(float64(w), float64(h))
+
+	background := canvas.NewRectangle(chartBackground)
+	background.Resize(fyne.NewSize(w, h))
+	objects := []fyne.CanvasObject{background}
+
+	for _, l := range lines {
+		line := canvas.NewLine(l.color)
+		line.StrokeWidth = float32(l.width)
+		line.Position1 = fyne.NewPos(float32(l.x1), float32(l.y1))
+		line.Position2 = fyne.NewPos(float32(l.x2), float32(l.y2))
+		objects = append(objects, line)
+	}
+
+	for _, t := range texts {
+		text := canvas.NewText(t.text, t.color)
+		text.TextSize = 12
+		text.TextStyle = fyne.TextStyle{Bold: t.bold}
+		size := fyne.MeasureText(t.text, text.TextSize, text.TextStyle)
+		x := float32(t.x)
+		switch t.align {
+		case 0:
+			x -= size.Width / 2
+		case 1:
+			x -= size.Width
+		}
+		text.Move(fyne.NewPos(x, float32(t.y)-size.Height+3))
+		text.Resize(size)
+		objects = append(objects, text)
+	}
+
+	return container.NewGridWrap(fyne.NewSize(w, h), container.NewWithoutLayout(objects...))
+}
+
+// svg colour for a chart colour
+func svgColor(c color.NRGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// writeSVG writes the chart out as an svg image w by h pixels
+func (c *lineChart) writeSVG(out io.Writer, w, h int) error {
+	lines, texts := c.layout(float64(w), float64(h))
+
+	svg := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", w, h, w, h)
+	svg += fmt.Sprintf(`<rect width="%d" height="%d" fill="%s"/>`+"\n", w, h, svgColor(chartBackground))
+	for _, l := range lines {
+		svg += fmt.Sprintf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="%g" stroke-linecap="round"/>`+"\n",
+			l.x1, l.y1, l.x2, l.y2, svgColor(l.color), l.width)
+	}
+	for _, t := range texts {
+		anchor := "middle"
+		switch t.align {
+		case -1:
+			anchor = "start"
+		case 1:
+			anchor = "end"
+		}
+		weight := "normal"
+		if t.bold {
+			weight = "bold"
+		}
+		svg += fmt.Sprintf(`<text x="%.1f" y="%.1f" fill="%s" font-family="sans-serif" font-size="12" font-weight="%s" text-anchor="%s">%s</text>`+"\n",
+			t.x, t.y, svgColor(t.color), weight, anchor, html.EscapeString(t.text))
+	}
+	svg += "</svg>\n"
+
+	_, err := io.WriteString(out, svg)
+	return err
+}
+
+// writePNG draws the chart into a png w by h pixels. it's all done in memory,
+// so it works without a display
+func (c *lineChart) writePNG(out io.Writer, w, h int) error {
+	lines, texts := c.layout(float64(w), float64(h))
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(chartBackground), image.Point{}, draw.Src)
+
+	for _, l := range lines {
+		drawLine(img, l)
+	}
+
+	face := basicfont.Face7x13
+	for _, t := range texts {
+		drawer := &font.Drawer{Dst: img, Src: image.NewUniform(t.color), Face: face}
+		width := drawer.MeasureString(t.text).Round()
+		x := int(t.x)
+		switch t.align {
+		case 0:
+			x -= width / 2
+		case 1:
+			x -= width
+		}
+		drawer.Dot = fixed.P(x, int(t.y))
+		drawer.DrawString(t.text)
+		if t.bold {
+			// no bold face, so draw it again a pixel over
+			drawer.Dot = fixed.P(x+1, int(t.y))
+			drawer.DrawString(t.text)
+		}
+	}
+
+	return png.Encode(out, img)
+}
+
+// draw a line into an image by stamping a square pen along it
+func drawLine(img *image.NRGBA, l chartLine) {
+	dx, dy := l.x2-l.x1, l.y2-l.y1
+	steps := int(math.Max(math.Abs(dx), math.Abs(dy))*2) + 1
+	half := l.width / 2
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		x, y := l.x1+dx*t, l.y1+dy*t
+		for px := int(math.Round(x - half)); px < int(math.Round(x+half)); px++ {
+			for py := int(math.Round(y - half)); py < int(math.Round(y+half)); py++ {
+				img.SetNRGBA(px, py, l.color)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// reportEngine picks up the last season for a report. reports only read, so with no season
+// saved there's nothing to report on rather than a new one to set up
+func reportEngine() *SeasonEngine {
+	engine := &SeasonEngine{}
+	if err := engine.resumeSeason(); err != nil {
+		fmt.Fprintf(os.Stderr, "no season to report on: %v\n", err)
+		os.Exit(1)
+	}
+	return engine
+}
+
+// printLeverage ranks the current season's remaining fixtures on the command line
+func printLeverage(weeks, simulations int) {
+	engine := reportEngine()
+	engine.Read(func(league *League) {
+		lastWeek := len(league.Fixtures)
+		if weeks > 0 {
+			lastWeek = league.upcomingWeek() + weeks - 1
+		}
+
+		fmt.Printf("Working out leverage from week %d to week %d (%d simulations per result)...\n\n",
+			league.upcomingWeek(), lastWeek, simulations)
+		fmt.Print(generateLeverageReport(league, league.RankFixtures(lastWeek, simulations)))
+	})
+}
+
+// printRequirements shows what a team in the current season needs on the command line
+func printRequirements(teamName string, position int) {
+	engine := reportEngine()
+	var report string
+	var err error
+	engine.Read(func(league *League) {
+		team := findTeam(league.Teams, teamName)
+		if team == nil {
+			names := make([]string, len(league.Teams))
+			for i, t := range league.Teams {
+				names[i] = "  " + t.Name
+			}
+			err = fmt.Errorf("%s isn't in the current season\n%s", teamName, strings.Join(names, "\n"))
+			return
+		}
+		if position < 1 || position > len(league.Teams) {
+			err = fmt.Errorf("position must be between 1 and %d", len(league.Teams))
+			return
+		}
+		report = generateRequirementsReport(league.Requirements(team, position))
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(report)
+}
+
+// printHeadToHead compares two teams in the current season on the command line
+func printHeadToHead(teamA, teamB string) {
+	engine := reportEngine()
+	var report string
+	var err error
+	engine.Read(func(league *League) {
+		report, err = headToHeadReport(league, teamA, teamB)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(report)
+}
+
+// exportTrendChart saves one of the current season's charts without opening a window
+func exportTrendChart(path, what string) {
+	if what != TrendTitle && what != TrendPosition && what != TrendStandings {
+		fmt.Fprintf(os.Stderr, "-plot must be %s, %s or %s\n", TrendTitle, TrendPosition, TrendStandings)
+		os.Exit(1)
+	}
+
+	engine := reportEngine()
+	var err error
+	engine.Read(func(league *League) {
+		chart := league.trendChart(what)
+		if what == TrendStandings {
+			chart = league.bumpChart()
+		}
+		err = exportChart(chart, path)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to export chart: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Saved %s\n", path)
+}
+
+// startNewLeague sets up a new league from the command line, from the config with any
+// teams, strengths or seed given on top. later commands and the gui carry on with it
+func startNewLeague(teams, strengths string, seed int64, seedSet bool) {
+	config := leagueConfig
+	var err error
+	if strengths != "" {
+		var parsed map[string]int
+		if parsed, err = parseStrengths(strengths); err == nil {
+			config, err = config.withStrengths(parsed)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "-strength: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if teams != "" {
+		if config, err = config.withTeams(strings.Split(teams, ",")); err != nil {
+			fmt.Fprintf(os.Stderr, "-teams: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if seedSet {
+		config.Seed = seed
+	}
+
+	// no point picking up the last season just to put it away
+	engine := &SeasonEngine{}
+	if err := engine.StartLeague(config); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to start a new league: %v\n", err)
+		os.Exit(1)
+	}
+	engine.Read(func(league *League) {
+		fmt.Printf("Started %s %s with %d weeks to play:\n", league.Name, league.Season, len(league.Fixtures))
+		for _, team := range league.Teams {
+			fmt.Printf("  %-20s %d\n", team.Name, team.BaseStrength)
+		}
+		fmt.Println()
+	})
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/color"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// where the league config is read from unless -config says otherwise
+const defaultLeagueConfigFile = "league.toml"
+
+// LeagueConfig describes a competition: who's in it, how often they play each other,
+// how it's scored and how matches are simulated. it's read from a toml or yaml file
+type LeagueConfig struct {
+	Name        string           `toml:"name" yaml:"name"`
+	Season      string           `toml:"season" yaml:"season"`             // blank for the current year
+	TeamCount   int              `toml:"team_count" yaml:"team_count"`     // how many of the teams to pick at random, 0 for all of them
+	Rounds      int              `toml:"rounds" yaml:"rounds"`             // times every team plays every other, home and away in turn
+	Seed        int64            `toml:"seed" yaml:"seed"`                 // the same seed picks the same teams and plays out the same results, 0 for random
+	Points      PointsRules      `toml:"points" yaml:"points"`             // what a win, draw and loss are worth
+	TieBreakers []string         `toml:"tie_breakers" yaml:"tie_breakers"` // what separates teams level on points, in order
+	MatchModel  MatchModelConfig `toml:"match_model" yaml:"match_model"`
+	MonteCarlo  MonteCarloConfig `toml:"monte_carlo" yaml:"monte_carlo"`
+	Play        PlayConfig       `toml:"play" yaml:"play"`
+	Calendar    CalendarConfig   `toml:"calendar" yaml:"calendar"` // when the weeks are played
+	Teams       []TeamConfig     `toml:"teams" yaml:"teams"`
+}
+
+// MatchModelConfig is how simulated matches are decided
+type MatchModelConfig struct {
+	Type            string  `toml:"type" yaml:"type"`                           // strength or poisson
+	DrawChance      float64 `toml:"draw_chance" yaml:"draw_chance"`             // strength: chance of a draw on top of the home side's win chance
+	MaxWinningGoals int     `toml:"max_winning_goals" yaml:"max_winning_goals"` // strength: the winner scores 1 to this many
+	AverageGoals    float64 `toml:"average_goals" yaml:"average_goals"`         // poisson: goals in an average match
+	FormWeight      float64 `toml:"form_weight" yaml:"form_weight"`             // how far five straight wins (or losses) move strength
+	FormCap         float64 `toml:"form_cap" yaml:"form_cap"`                   // furthest form can take strength from its base
+}
+
+// MonteCarloConfig is how hard the probabilities are worked out
+type MonteCarloConfig struct {
+	Simulations int `toml:"simulations" yaml:"simulations"` // seasons simulated for the probability table
+}
+
+// PlayConfig covers playing through the season in the gui
+type PlayConfig struct {
+	PlayAllDelayMS int `toml:"play_all_delay_ms" yaml:"play_all_delay_ms"` // gap between weeks in play all at normal speed
+	MaxGoals       int `toml:"max_goals" yaml:"max_goals"`                 // most goals a result can be edited to
+}
+
+// TeamConfig is a team that can be picked for the league
+type TeamConfig struct {
+	Name      string `toml:"name" yaml:"name"`
+	ShortName string `toml:"short_name" yaml:"short_name"` // three letters for tight spaces, the first three of the name if left out
+	Strength  int    `toml:"strength" yaml:"strength"`
+	Color     string `toml:"color" yaml:"color"` // #rrggbb for the team's badge, optional
+}
+
+// the league config new leagues are set up from and the file it came from.
+// like settings, only changed with the engine's lock held
+var (
+	leagueConfig     = builtinLeagueConfig()
+	leagueConfigPath = defaultLeagueConfigFile
+)
+
+// the league the simulator has always played: 4 of the 10 built in clubs, six times round
+func builtinLeagueConfig() LeagueConfig {
+	c := LeagueConfig{
+		Name:      "Premier League Mini",
+		TeamCount: 4,
+		Rounds:    6,
+		Calendar:  builtinCalendar(),
+	}
+	c.setSettings(builtinSettings())
+	for _, team := range getMockPremierLeagueTeams() {
+		c.Teams = append(c.Teams, TeamConfig{
+			Name:      team.Name,
+			ShortName: team.ShortName,
+			Strength:  team.BaseStrength,
+			Color:     fmt.Sprintf("#%02x%02x%02x", team.Color.R, team.Color.G, team.Color.B),
+		})
+	}
+	return c
+}
+
+// settings the config starts a league with
+func (c LeagueConfig) settings() SimulationSettings {
+	return SimulationSettings{
+		MatchModel:      c.MatchModel.Type,
+		DrawChance:      c.MatchModel.DrawChance,
+		MaxWinningGoals: c.MatchModel.MaxWinningGoals,
+		AverageGoals:    c.MatchModel.AverageGoals,
+		FormWeight:      c.MatchModel.FormWeight,
+		FormCap:         c.MatchModel.FormCap,
+		Simulations:     c.MonteCarlo.Simulations,
+		PlayAllDelayMS:  c.Play.PlayAllDelayMS,
+		MaxGoals:        c.Play.MaxGoals,
+		Points:          c.Points,
+		TieBreakers:     append([]string(nil), c.TieBreakers...),
+	}
+}
+
+// make s the settings the config starts leagues with
+func (c *LeagueConfig) setSettings(s SimulationSettings) {
+	c.MatchModel = MatchModelConfig{
+		Type:            s.MatchModel,
+		DrawChance:      s.DrawChance,
+		MaxWinningGoals: s.MaxWinningGoals,
+		AverageGoals:    s.AverageGoals,
+		FormWeight:      s.FormWeight,
+		FormCap:         s.FormCap,
+	}
+	c.MonteCarlo.Simulations = s.Simulations
+	c.Play = PlayConfig{PlayAllDelayMS: s.PlayAllDelayMS, MaxGoals: s.MaxGoals}
+	c.Points = s.Points
+	c.TieBreakers = append([]string(nil), s.TieBreakers...)
+}
+
+// how many teams a league gets
+func (c LeagueConfig) teamCount() int {
+	if c.TeamCount == 0 {
+		return len(c.Teams)
+	}
+	return c.TeamCount
+}
+
+// validate checks the config describes a league that can be played
+func (c LeagueConfig) validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("the league needs a name")
+	}
+	if c.Rounds < 1 || c.Rounds > 20 {
+		return fmt.Errorf("rounds has to be between 1 and 20")
+	}
+	if c.TeamCount < 0 || c.TeamCount == 1 {
+		return fmt.Errorf("team_count has to be 0 (all of them) or at least 2")
+	}
+	if c.teamCount() > len(c.Teams) {
+		return fmt.Errorf("team_count is %d but only %d teams are listed", c.TeamCount, len(c.Teams))
+	}
+	if len(c.Teams) < 2 {
+		return fmt.Errorf("a league needs at least 2 teams")
+	}
+
+	seen := make(map[string]bool)
+	for i, team := range c.Teams {
+		if strings.TrimSpace(team.Name) == "" {
+			return fmt.Errorf("team %d has no name", i+1)
+		}
+		if seen[team.Name] {
+			return fmt.Errorf("%s is listed twice", team.Name)
+		}
+		seen[team.Name] = true
+		if team.Strength < 1 || team.Strength > 200 {
+			return fmt.Errorf("%s: strength has to be between 1 and 200", team.Name)
+		}
+		if team.Color != "" {
+			if _, err := parseColor(team.Color); err != nil {
+				return fmt.Errorf("%s: %v", team.Name, err)
+			}
+		}
+	}
+	if err := c.Calendar.validate(); err != nil {
+		return err
+	}
+	return c.settings().validate()
+}
+
+// pickTeams picks the teams for a new league, in a random order
+func (c LeagueConfig) pickTeams(r *rand.Rand) []TeamConfig {
+	teams := append([]TeamConfig(nil), c.Teams...)
+	r.Shuffle(len(teams), func(i, j int) {
+		teams[i], teams[j] = teams[j], teams[i]
+	})
+	return teams[:c.teamCount()]
+}
+
+// the config for a team, false if it isn't listed
+func (c LeagueConfig) team(name string) (TeamConfig, bool) {
+	for _, team := range c.Teams {
+		if team.Name == name {
+			return team, true
+		}
+	}
+	return TeamConfig{}, false
+}
+
+// parse a #rrggbb colour
+func parseColor(s string) (color.NRGBA, error) {
+	var c color.NRGBA
+	if len(s) != 7 || s[0] != '#' {
+		return c, fmt.Errorf("colour %q has to be #rrggbb", s)
+	}
+	if _, err := fmt.Sscanf(s[1:], "%02x%02x%02x", &c.R, &c.G, &c.B); err != nil {
+		return c, fmt.Errorf("colour %q has to be #rrggbb", s)
+	}
+	c.A = 255
+	return c, nil
+}
+
+// SettingsDefaults is the part of a league config "Save as Default" writes, kept in a file of
+// its own so the league config stays as it was written
+type SettingsDefaults struct {
+	Points      PointsRules      `toml:"points" yaml:"points"`
+	TieBreakers []string         `toml:"tie_breakers" yaml:"tie_breakers"`
+	MatchModel  MatchModelConfig `toml:"match_model" yaml:"match_model"`
+	MonteCarlo  MonteCarloConfig `toml:"monte_carlo" yaml:"monte_carlo"`
+	Play        PlayConfig       `toml:"play" yaml:"play"`
+}
+
+// where the saved default settings for a league config go, league.toml's in league.defaults.toml
+func defaultsPath(path string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + ".defaults" + ext
+}
+
+// loadLeagueConfig reads a league config, toml or yaml depending on the extension, with any
+// saved default settings on top. anything the files leave out keeps its built in value, and
+// no file at all means all of it does
+func loadLeagueConfig(path string) (LeagueConfig, error) {
+	c := builtinLeagueConfig()
+	if err := decodeConfigFile(path, &c); err != nil {
+		return c, err
+	}
+
+	var defaults SettingsDefaults
+	defaults.fill(c)
+	if err := decodeConfigFile(defaultsPath(path), &defaults); err != nil {
+		return c, err
+	}
+	c.Points = defaults.Points
+	c.TieBreakers = defaults.TieBreakers
+	c.MatchModel = defaults.MatchModel
+	c.MonteCarlo = defaults.MonteCarlo
+	c.Play = defaults.Play
+
+	if err := c.validate(); err != nil {
+		return c, fmt.Errorf("%s: %v", path, err)
+	}
+	return c, nil
+}
+
+// take the settings from a league config
+func (d *SettingsDefaults) fill(c LeagueConfig) {
+	d.Points = c.Points
+	d.TieBreakers = append([]string(nil), c.TieBreakers...)
+	d.MatchModel = c.MatchModel
+	d.MonteCarlo = c.MonteCarlo
+	d.Play = c.Play
+}
+
+// decode a toml or yaml file over v, leaving v alone if there's no file
+func decodeConfigFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		meta, err := toml.Decode(string(data), v)
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		// a typo would otherwise quietly leave the built in value in place
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("%s: unknown setting %s", path, undecoded[0])
+		}
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(v); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	default:
+		return fmt.Errorf("can't read %s, use .toml, .yaml or .yml", path)
+	}
+	return nil
+}
+
+// saveSettingsDefaults writes s out as the default settings for the league config at path,
+// leaving the config itself alone. returns the file it went to
+func saveSettingsDefaults(path string, s SimulationSettings) (string, error) {
+	if err := s.validate(); err != nil {
+		return "", err
+	}
+	var c LeagueConfig
+	c.setSettings(s)
+	var defaults SettingsDefaults
+	defaults.fill(c)
+
+	path = defaultsPath(path)
+	var buf bytes.Buffer
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		if err := toml.NewEncoder(&buf).Encode(defaults); err != nil {
+			return "", err
+		}
+	case ".yaml", ".yml":
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(defaults); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("can't write %s, use .toml, .yaml or .yml", path)
+	}
+	return path, os.WriteFile(path, buf.Bytes(), 0644)
+}
@@ -2,7 +2,10 @@ package main
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -73,9 +76,14 @@ func (d *Database) createTables() error {
 		away_team_id INTEGER NOT NULL,
 		home_goals INTEGER DEFAULT 0,
 		away_goals INTEGER DEFAULT 0,
+		home_penalties INTEGER DEFAULT 0, -- a level match's shootout, both 0 if there wasn't one
+		away_penalties INTEGER DEFAULT 0,
 		is_played BOOLEAN DEFAULT FALSE,
 		is_fixed BOOLEAN DEFAULT FALSE,
-		match_date DATETIME,
+		status VARCHAR(20) DEFAULT '', -- postponed, abandoned or walkover, blank as scheduled
+		play_week INTEGER DEFAULT 0, -- the week a rearranged match is now played in, 0 for its own
+		midweek BOOLEAN DEFAULT FALSE, -- rearranged into the midweek before play_week's round
+		match_date DATETIME, -- kickoff
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (league_id) REFERENCES leagues(id),
@@ -90,6 +98,7 @@ func (d *Database) createTables() error {
 		league_id INTEGER NOT NULL,
 		team_id INTEGER NOT NULL,
 		position INTEGER DEFAULT 0,
+		base_strength INTEGER, -- the team's strength in this league, null for leagues saved before it was kept
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (league_id) REFERENCES leagues(id),
 		FOREIGN KEY (team_id) REFERENCES teams(id),
@@ -104,12 +113,221 @@ func (d *Database) createTables() error {
 		team_id INTEGER NOT NULL,
 		week INTEGER NOT NULL,
 		probability REAL NOT NULL,
+		expected_position REAL, -- average finishing position
 		calculated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (league_id) REFERENCES leagues(id),
 		FOREIGN KEY (team_id) REFERENCES teams(id)
 	);`
 
-	tables := []string{teamsTable, leaguesTable, matchesTable, leagueTeamsTable, probabilitiesTable}
+	// people taking part in the predictor league
+	predictorsTable := `
+	CREATE TABLE IF NOT EXISTS predictors (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name VARCHAR(100) NOT NULL UNIQUE,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	// predicted scores, one per predictor per fixture
+	predictionsTable := `
+	CREATE TABLE IF NOT EXISTS predictions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		league_id INTEGER NOT NULL,
+		predictor_id INTEGER NOT NULL,
+		week INTEGER NOT NULL,
+		home_team_id INTEGER NOT NULL,
+		away_team_id INTEGER NOT NULL,
+		home_goals INTEGER NOT NULL,
+		away_goals INTEGER NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (league_id) REFERENCES leagues(id),
+		FOREIGN KEY (predictor_id) REFERENCES predictors(id),
+		FOREIGN KEY (home_team_id) REFERENCES teams(id),
+		FOREIGN KEY (away_team_id) REFERENCES teams(id),
+		UNIQUE(league_id, predictor_id, week, home_team_id, away_team_id)
+	);`
+
+	// squad players for each team
+	playersTable := `
+	CREATE TABLE IF NOT EXISTS players (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		team_id INTEGER NOT NULL,
+		name VARCHAR(100) NOT NULL,
+		position VARCHAR(3) NOT NULL, -- GK, DEF, MID, FWD
+		rating INTEGER NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (team_id) REFERENCES teams(id),
+		UNIQUE(team_id, name)
+	);`
+
+	// goals and assists per player per league
+	playerStatsTable := `
+	CREATE TABLE IF NOT EXISTS player_stats (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		league_id INTEGER NOT NULL,
+		player_id INTEGER NOT NULL,
+		goals INTEGER DEFAULT 0,
+		assists INTEGER DEFAULT 0,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (league_id) REFERENCES leagues(id),
+		FOREIGN KEY (player_id) REFERENCES players(id),
+		UNIQUE(league_id, player_id)
+	);`
+
+	// everything that happened in a match, minute by minute
+	matchEventsTable := `
+	CREATE TABLE IF NOT EXISTS match_events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		match_id INTEGER NOT NULL,
+		minute INTEGER NOT NULL,
+		event_type VARCHAR(10) NOT NULL, -- goal, yellow, red, sub, injury
+		team_id INTEGER NOT NULL,
+		player_id INTEGER,
+		other_player_id INTEGER, -- assist, or the player coming on
+		weeks_out INTEGER DEFAULT 0, -- injuries only
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (match_id) REFERENCES matches(id),
+		FOREIGN KEY (team_id) REFERENCES teams(id),
+		FOREIGN KEY (player_id) REFERENCES players(id),
+		FOREIGN KEY (other_player_id) REFERENCES players(id)
+	);`
+
+	// undo history, the matches each change touched as json before and after
+	historyTable := `
+	CREATE TABLE IF NOT EXISTS league_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		league_id INTEGER NOT NULL,
+		seq INTEGER NOT NULL,
+		action VARCHAR(10) NOT NULL, -- simulate, edit, clear
+		description VARCHAR(200) NOT NULL,
+		week_before INTEGER NOT NULL,
+		week_after INTEGER NOT NULL,
+		before_state TEXT NOT NULL,
+		after_state TEXT NOT NULL,
+		undone BOOLEAN DEFAULT FALSE, -- on the redo stack
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (league_id) REFERENCES leagues(id),
+		UNIQUE(league_id, seq)
+	);`
+
+	// named what-if scenarios for a league
+	scenariosTable := `
+	CREATE TABLE IF NOT EXISTS scenarios (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		league_id INTEGER NOT NULL,
+		name VARCHAR(100) NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (league_id) REFERENCES leagues(id),
+		UNIQUE(league_id, name)
+	);`
+
+	// results pinned by a scenario
+	scenarioPinsTable := `
+	CREATE TABLE IF NOT EXISTS scenario_pins (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		scenario_id INTEGER NOT NULL,
+		week INTEGER NOT NULL,
+		home_team_id INTEGER NOT NULL,
+		away_team_id INTEGER NOT NULL,
+		home_goals INTEGER NOT NULL,
+		away_goals INTEGER NOT NULL,
+		FOREIGN KEY (scenario_id) REFERENCES scenarios(id),
+		FOREIGN KEY (home_team_id) REFERENCES teams(id),
+		FOREIGN KEY (away_team_id) REFERENCES teams(id)
+	);`
+
+	// team strength tweaks in a scenario
+	scenarioStrengthsTable := `
+	CREATE TABLE IF NOT EXISTS scenario_strengths (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		scenario_id INTEGER NOT NULL,
+		team_id INTEGER NOT NULL,
+		adjustment INTEGER NOT NULL,
+		FOREIGN KEY (scenario_id) REFERENCES scenarios(id),
+		FOREIGN KEY (team_id) REFERENCES teams(id),
+		UNIQUE(scenario_id, team_id)
+	);`
+
+	// the table after each week, for the position chart
+	positionsTable := `
+	CREATE TABLE IF NOT EXISTS league_positions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		league_id INTEGER NOT NULL,
+		week INTEGER NOT NULL,
+		team_id INTEGER NOT NULL,
+		position INTEGER NOT NULL,
+		points INTEGER NOT NULL,
+		FOREIGN KEY (league_id) REFERENCES leagues(id),
+		FOREIGN KEY (team_id) REFERENCES teams(id),
+		UNIQUE(league_id, week, team_id)
+	);`
+
+	// the numbers each league's simulation runs on
+	settingsTable := `
+	CREATE TABLE IF NOT EXISTS league_settings (
+		league_id INTEGER PRIMARY KEY,
+		match_model VARCHAR(20) NOT NULL DEFAULT 'strength', -- strength, poisson
+		draw_chance REAL NOT NULL,
+		max_winning_goals INTEGER NOT NULL,
+		average_goals REAL NOT NULL DEFAULT 2.7,
+		form_weight REAL NOT NULL,
+		form_cap REAL NOT NULL,
+		simulations INTEGER NOT NULL,
+		play_all_delay_ms INTEGER NOT NULL,
+		max_goals INTEGER NOT NULL,
+		points_win INTEGER NOT NULL DEFAULT 3,
+		points_draw INTEGER NOT NULL DEFAULT 1,
+		points_loss INTEGER NOT NULL DEFAULT 0,
+		goals_bonus INTEGER NOT NULL DEFAULT 0,
+		goals_bonus_at INTEGER NOT NULL DEFAULT 4,
+		losing_bonus INTEGER NOT NULL DEFAULT 0,
+		shootouts BOOLEAN NOT NULL DEFAULT 0,
+		shootout_win INTEGER NOT NULL DEFAULT 2,
+		shootout_loss INTEGER NOT NULL DEFAULT 1,
+		tie_breakers VARCHAR(100) NOT NULL DEFAULT 'goal_difference', -- comma separated, in order
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (league_id) REFERENCES leagues(id)
+	);`
+
+	// points taken off (or given back to) a team by the league
+	adjustmentsTable := `
+	CREATE TABLE IF NOT EXISTS points_adjustments (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		league_id INTEGER NOT NULL,
+		team_id INTEGER NOT NULL,
+		week INTEGER NOT NULL, -- counts in the table from the end of this week, 0 from the start
+		points INTEGER NOT NULL, -- negative for a deduction
+		reason VARCHAR(200) NOT NULL,
+		adjustment_date DATE NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (league_id) REFERENCES leagues(id),
+		FOREIGN KEY (team_id) REFERENCES teams(id)
+	);`
+
+	// when each week of a league is played
+	roundsTable := `
+	CREATE TABLE IF NOT EXISTS league_rounds (
+		league_id INTEGER NOT NULL,
+		week INTEGER NOT NULL,
+		round_date DATE NOT NULL, -- the saturday, or the tuesday of a midweek round
+		midweek BOOLEAN DEFAULT FALSE,
+		PRIMARY KEY (league_id, week),
+		FOREIGN KEY (league_id) REFERENCES leagues(id)
+	);`
+
+	// the calendar a league's dates came from
+	calendarsTable := `
+	CREATE TABLE IF NOT EXISTS league_calendars (
+		league_id INTEGER PRIMARY KEY,
+		calendar TEXT NOT NULL, -- the calendar config as json
+		FOREIGN KEY (league_id) REFERENCES leagues(id)
+	);`
+
+	tables := []string{teamsTable, leaguesTable, matchesTable, leagueTeamsTable, probabilitiesTable,
+		predictorsTable, predictionsTable, playersTable, playerStatsTable, matchEventsTable, historyTable,
+		scenariosTable, scenarioPinsTable, scenarioStrengthsTable, positionsTable, settingsTable, adjustmentsTable,
+		roundsTable, calendarsTable}
 
 	for _, table := range tables {
 		if _, err := d.db.Exec(table); err != nil {
@@ -117,6 +335,66 @@ func (d *Database) createTables() error {
 		}
 	}
 
+	// one row per fixture so saving a match again updates it. a database from before
+	// the unique index could have picked up duplicates, keep the latest copy of those.
+	// only done the once, before the index goes on
+	hasFixtureIndex, err := d.indexExists("idx_matches_fixture")
+	if err != nil {
+		return err
+	}
+	if !hasFixtureIndex {
+		dedupeMatches := `
+		DELETE FROM matches WHERE id NOT IN (
+			SELECT MAX(id) FROM matches GROUP BY league_id, week, home_team_id, away_team_id
+		)`
+		result, err := d.db.Exec(dedupeMatches)
+		if err != nil {
+			return fmt.Errorf("failed to clean up matches: %v", err)
+		}
+		if removed, _ := result.RowsAffected(); removed > 0 {
+			fmt.Printf("Removed %d duplicate matches saved by an older version\n", removed)
+		}
+	}
+
+	// columns added since the tables were first created
+	columns := []struct{ table, column, definition string }{
+		{"championship_probabilities", "expected_position", "REAL"},
+		{"league_settings", "match_model", "VARCHAR(20) NOT NULL DEFAULT 'strength'"},
+		{"league_settings", "average_goals", "REAL NOT NULL DEFAULT 2.7"},
+		{"league_settings", "points_win", "INTEGER NOT NULL DEFAULT 3"},
+		{"league_settings", "points_draw", "INTEGER NOT NULL DEFAULT 1"},
+		{"league_settings", "points_loss", "INTEGER NOT NULL DEFAULT 0"},
+		{"league_settings", "tie_breakers", "VARCHAR(100) NOT NULL DEFAULT 'goal_difference'"},
+		{"league_teams", "base_strength", "INTEGER"},
+		{"matches", "home_penalties", "INTEGER DEFAULT 0"},
+		{"matches", "away_penalties", "INTEGER DEFAULT 0"},
+		{"league_settings", "goals_bonus", "INTEGER NOT NULL DEFAULT 0"},
+		{"league_settings", "goals_bonus_at", "INTEGER NOT NULL DEFAULT 4"},
+		{"league_settings", "losing_bonus", "INTEGER NOT NULL DEFAULT 0"},
+		{"league_settings", "shootouts", "BOOLEAN NOT NULL DEFAULT 0"},
+		{"league_settings", "shootout_win", "INTEGER NOT NULL DEFAULT 2"},
+		{"league_settings", "shootout_loss", "INTEGER NOT NULL DEFAULT 1"},
+		{"matches", "status", "VARCHAR(20) DEFAULT ''"},
+		{"matches", "play_week", "INTEGER DEFAULT 0"},
+		{"matches", "midweek", "BOOLEAN DEFAULT FALSE"},
+	}
+	for _, c := range columns {
+		if err := d.addColumn(c.table, c.column, c.definition); err != nil {
+			return fmt.Errorf("failed to add %s.%s: %v", c.table, c.column, err)
+		}
+	}
+
+	indexes := []string{
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_matches_fixture ON matches(league_id, week, home_team_id, away_team_id)",
+		"CREATE INDEX IF NOT EXISTS idx_match_events_match ON match_events(match_id)",
+		"CREATE INDEX IF NOT EXISTS idx_points_adjustments_league ON points_adjustments(league_id)",
+	}
+	for _, index := range indexes {
+		if _, err := d.db.Exec(index); err != nil {
+			return fmt.Errorf("failed to create index: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -127,11 +405,26 @@ func (d *Database) SaveTeam(team *Team) (int64, error) {
 		return 0, fmt.Errorf("invalid team data: team name is required")
 	}
 
+	// upsert so the team keeps its id - other tables point at it
 	query := `
-	INSERT OR REPLACE INTO teams 
+	INSERT INTO teams 
 	(name, short_name, base_strength, current_strength, played, won, drawn, lost, 
 	 goals_for, goals_against, goal_difference, points, form, updated_at)
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	ON CONFLICT(name) DO UPDATE SET
+		short_name = excluded.short_name,
+		base_strength = excluded.base_strength,
+		current_strength = excluded.current_strength,
+		played = excluded.played,
+		won = excluded.won,
+		drawn = excluded.drawn,
+		lost = excluded.lost,
+		goals_for = excluded.goals_for,
+		goals_against = excluded.goals_against,
+		goal_difference = excluded.goal_difference,
+		points = excluded.points,
+		form = excluded.form,
+		updated_at = CURRENT_TIMESTAMP`
 
 	formStr := ""
 	if team.Form != nil {
@@ -145,7 +438,7 @@ func (d *Database) SaveTeam(team *Team) (int64, error) {
 		}
 	}
 
-	result, err := d.db.Exec(query,
+	_, err := d.db.Exec(query,
 		team.Name, getShortName(team.Name), team.BaseStrength, team.CurrentStrength,
 		team.Played, team.Won, team.Drawn, team.Lost,
 		team.GoalsFor, team.GoalsAgainst, team.GoalDifference, team.Points, formStr)
@@ -154,7 +447,8 @@ func (d *Database) SaveTeam(team *Team) (int64, error) {
 		return 0, fmt.Errorf("failed to save team %s: %v", team.Name, err)
 	}
 
-	return result.LastInsertId()
+	// LastInsertId isn't reliable when the row was updated instead
+	return d.getTeamID(team.Name)
 }
 
 // save a new league to the database
@@ -171,6 +465,110 @@ func (d *Database) SaveLeague(league *League, name, season string) (int64, error
 	return result.LastInsertId()
 }
 
+// save the league along with its teams so other tables can refer to them
+func (d *Database) RegisterLeague(league *League, name, season string) (int64, error) {
+	leagueID, err := d.SaveLeague(league, name, season)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save league: %v", err)
+	}
+
+	for _, team := range league.Teams {
+		teamID, err := d.SaveTeam(team)
+		if err != nil {
+			return 0, err
+		}
+
+		// strengths can be changed for a league, so it keeps its own
+		_, err = d.db.Exec("INSERT OR IGNORE INTO league_teams (league_id, team_id, base_strength) VALUES (?, ?, ?)",
+			leagueID, teamID, team.BaseStrength)
+		if err != nil {
+			return 0, fmt.Errorf("failed to link team %s to league: %v", team.Name, err)
+		}
+
+		for _, player := range team.Squad {
+			if err := d.SavePlayer(teamID, player); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	// save the whole fixture list so the season can be picked up again
+	for week := range league.Fixtures {
+		for i := range league.Fixtures[week] {
+			if err := d.SaveMatch(leagueID, &league.Fixtures[week][i]); err != nil {
+				return 0, fmt.Errorf("failed to save fixture: %v", err)
+			}
+		}
+	}
+	for i, round := range league.Rounds {
+		_, err := d.db.Exec("INSERT OR REPLACE INTO league_rounds (league_id, week, round_date, midweek) VALUES (?, ?, ?, ?)",
+			leagueID, i+1, round.Date.Format(roundDateFormat), round.Midweek)
+		if err != nil {
+			return 0, fmt.Errorf("failed to save week %d's date: %v", i+1, err)
+		}
+	}
+	if len(league.Rounds) > 0 {
+		if err := d.SaveLeagueCalendar(leagueID, league.Calendar); err != nil {
+			return 0, err
+		}
+	}
+
+	return leagueID, nil
+}
+
+// keep the league's current week and status up to date
+func (d *Database) UpdateLeagueWeek(leagueID int64, week int, completed bool) error {
+	status := "active"
+	if completed {
+		status = "completed"
+	}
+	query := "UPDATE leagues SET current_week = ?, status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?"
+	_, err := d.db.Exec(query, week, status, leagueID)
+	return err
+}
+
+// get the most recently started league and the week it got to
+func (d *Database) GetLatestLeague() (int64, int, error) {
+	var leagueID int64
+	var week int
+	query := "SELECT id, current_week FROM leagues ORDER BY id DESC LIMIT 1"
+	err := d.db.QueryRow(query).Scan(&leagueID, &week)
+	return leagueID, week, err
+}
+
+// get a league's name and season
+func (d *Database) GetLeagueName(leagueID int64) (string, string, error) {
+	var name, season string
+	err := d.db.QueryRow("SELECT name, season FROM leagues WHERE id = ?", leagueID).Scan(&name, &season)
+	return name, season, err
+}
+
+// get the teams taking part in a league, in the order they were added
+func (d *Database) GetLeagueTeams(leagueID int64) ([]*Team, error) {
+	query := `
+	SELECT t.name, COALESCE(lt.base_strength, t.base_strength)
+	FROM teams t
+	JOIN league_teams lt ON t.id = lt.team_id
+	WHERE lt.league_id = ?
+	ORDER BY lt.id`
+
+	rows, err := d.db.Query(query, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []*Team
+	for rows.Next() {
+		team := &Team{}
+		if err := rows.Scan(&team.Name, &team.BaseStrength); err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+	return teams, rows.Err()
+}
+
 // save a match result to the database
 func (d *Database) SaveMatch(leagueID int64, match *Match) error {
 	// get the team ids first
@@ -184,19 +582,157 @@ func (d *Database) SaveMatch(leagueID int64, match *Match) error {
 		return err
 	}
 
+	// update in place so the match keeps its id for the events
 	query := `
-	INSERT OR REPLACE INTO matches 
-	(league_id, week, home_team_id, away_team_id, home_goals, away_goals, is_played, is_fixed, updated_at)
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`
+	INSERT INTO matches 
+	(league_id, week, home_team_id, away_team_id, home_goals, away_goals, home_penalties, away_penalties,
+	 is_played, is_fixed, status, play_week, midweek, match_date, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	ON CONFLICT(league_id, week, home_team_id, away_team_id) DO UPDATE SET
+		home_goals = excluded.home_goals,
+		away_goals = excluded.away_goals,
+		home_penalties = excluded.home_penalties,
+		away_penalties = excluded.away_penalties,
+		is_played = excluded.is_played,
+		is_fixed = excluded.is_fixed,
+		status = excluded.status,
+		play_week = excluded.play_week,
+		midweek = excluded.midweek,
+		match_date = excluded.match_date,
+		updated_at = CURRENT_TIMESTAMP`
+
+	// no kickoff for a league saved before there were dates
+	var kickoff any
+	if !match.Kickoff.IsZero() {
+		kickoff = match.Kickoff.Format(kickoffFormat)
+	}
 
 	_, err = d.db.Exec(query, leagueID, match.Week, homeTeamID, awayTeamID,
-		match.HomeGoals, match.AwayGoals, match.IsPlayed, match.IsFixed)
+		match.HomeGoals, match.AwayGoals, match.HomePenalties, match.AwayPenalties, match.IsPlayed, match.IsFixed,
+		match.Status, match.PlayWeek, match.Midweek, kickoff)
 
 	return err
 }
 
-// save championship probabilities for a specific week
-func (d *Database) SaveChampionshipProbabilities(leagueID int64, week int, probabilities map[string]float64) error {
+// getMatchID gets a match's ID by league, week and teams
+func (d *Database) getMatchID(leagueID int64, match *Match) (int64, error) {
+	var matchID int64
+	query := `
+	SELECT m.id FROM matches m
+	JOIN teams ht ON m.home_team_id = ht.id
+	JOIN teams at ON m.away_team_id = at.id
+	WHERE m.league_id = ? AND m.week = ? AND ht.name = ? AND at.name = ?`
+	err := d.db.QueryRow(query, leagueID, match.Week, match.HomeTeam.Name, match.AwayTeam.Name).Scan(&matchID)
+	return matchID, err
+}
+
+// replace the timeline stored for a match
+func (d *Database) SaveMatchEvents(leagueID int64, match *Match) error {
+	matchID, err := d.getMatchID(leagueID, match)
+	if err != nil {
+		return fmt.Errorf("failed to find match: %v", err)
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM match_events WHERE match_id = ?", matchID); err != nil {
+		return err
+	}
+
+	// players are looked up by team and name, subqueries give NULL when there's no player
+	insertQuery := `
+	INSERT INTO match_events (match_id, minute, event_type, team_id, player_id, other_player_id, weeks_out)
+	SELECT ?, ?, ?, t.id,
+		(SELECT p.id FROM players p WHERE p.team_id = t.id AND p.name = ?),
+		(SELECT p.id FROM players p WHERE p.team_id = t.id AND p.name = ?),
+		?
+	FROM teams t WHERE t.name = ?`
+
+	for _, event := range match.Events {
+		playerName, otherName := "", ""
+		if event.Player != nil {
+			playerName = event.Player.Name
+		}
+		if event.Other != nil {
+			otherName = event.Other.Name
+		}
+
+		_, err := tx.Exec(insertQuery, matchID, event.Minute, event.Type, playerName, otherName, event.Weeks, event.Team.Name)
+		if err != nil {
+			return fmt.Errorf("failed to save match event: %v", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// get the timeline stored for a match. teams and players only have their names filled in
+func (d *Database) GetMatchEvents(leagueID int64, match *Match) ([]MatchEvent, error) {
+	matchID, err := d.getMatchID(leagueID, match)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find match: %v", err)
+	}
+
+	query := `
+	SELECT e.minute, e.event_type, t.name, COALESCE(p.name, ''), COALESCE(o.name, ''), e.weeks_out
+	FROM match_events e
+	JOIN teams t ON e.team_id = t.id
+	LEFT JOIN players p ON e.player_id = p.id
+	LEFT JOIN players o ON e.other_player_id = o.id
+	WHERE e.match_id = ?
+	ORDER BY e.id`
+
+	rows, err := d.db.Query(query, matchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []MatchEvent
+	for rows.Next() {
+		var event MatchEvent
+		var teamName, playerName, otherName string
+		err := rows.Scan(&event.Minute, &event.Type, &teamName, &playerName, &otherName, &event.Weeks)
+		if err != nil {
+			return nil, err
+		}
+
+		event.Team = &Team{Name: teamName}
+		if playerName != "" {
+			event.Player = &Player{Name: playerName, TeamName: teamName}
+		}
+		if otherName != "" {
+			event.Other = &Player{Name: otherName, TeamName: teamName}
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// is there an index with this name
+func (d *Database) indexExists(name string) (bool, error) {
+	var count int
+	err := d.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = ?", name).Scan(&count)
+	return count > 0, err
+}
+
+// add a column to a table made by an older version, if it isn't there already
+func (d *Database) addColumn(table, column, definition string) error {
+	var count int
+	err := d.db.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+	_, err = d.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
+// save championship probabilities and expected finishing positions for a specific week
+func (d *Database) SaveChampionshipProbabilities(leagueID int64, week int, probabilities, expectedPositions map[string]float64) error {
 	// clear out old probabilities for this league and week
 	deleteQuery := "DELETE FROM championship_probabilities WHERE league_id = ? AND week = ?"
 	_, err := d.db.Exec(deleteQuery, leagueID, week)
@@ -206,8 +742,8 @@ func (d *Database) SaveChampionshipProbabilities(leagueID int64, week int, proba
 
 	// add the new probabilities
 	insertQuery := `
-	INSERT INTO championship_probabilities (league_id, team_id, week, probability)
-	VALUES (?, ?, ?, ?)`
+	INSERT INTO championship_probabilities (league_id, team_id, week, probability, expected_position)
+	VALUES (?, ?, ?, ?, ?)`
 
 	for teamName, prob := range probabilities {
 		teamID, err := d.getTeamID(teamName)
@@ -215,15 +751,661 @@ func (d *Database) SaveChampionshipProbabilities(leagueID int64, week int, proba
 			continue // skip if we can't find the team
 		}
 
-		_, err = d.db.Exec(insertQuery, leagueID, teamID, week, prob)
+		_, err = d.db.Exec(insertQuery, leagueID, teamID, week, prob, expectedPositions[teamName])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// throw away probabilities saved for weeks after week, once those weeks have been undone
+func (d *Database) DeleteProbabilitiesAfter(leagueID int64, week int) error {
+	_, err := d.db.Exec("DELETE FROM championship_probabilities WHERE league_id = ? AND week > ?", leagueID, week)
+	return err
+}
+
+// get the probabilities saved for a league week by week, oldest first
+func (d *Database) GetProbabilityHistory(leagueID int64) ([]WeekProbabilities, error) {
+	query := `
+	SELECT cp.week, t.name, cp.probability, COALESCE(cp.expected_position, 0)
+	FROM championship_probabilities cp
+	JOIN teams t ON cp.team_id = t.id
+	WHERE cp.league_id = ?
+	ORDER BY cp.week, t.name`
+
+	rows, err := d.db.Query(query, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []WeekProbabilities
+	for rows.Next() {
+		var week int
+		var name string
+		var prob, expected float64
+		if err := rows.Scan(&week, &name, &prob, &expected); err != nil {
+			return nil, err
+		}
+		if len(history) == 0 || history[len(history)-1].Week != week {
+			history = append(history, WeekProbabilities{
+				Week:             week,
+				Title:            make(map[string]float64),
+				ExpectedPosition: make(map[string]float64),
+			})
+		}
+		history[len(history)-1].Title[name] = prob
+		history[len(history)-1].ExpectedPosition[name] = expected
+	}
+	return history, rows.Err()
+}
+
+// GetHeadToHead gets every played match between two teams in any league except excludeLeagueID,
+// oldest first. the current league is left out as its matches are already in memory
+func (d *Database) GetHeadToHead(teamA, teamB string, excludeLeagueID int64) ([]HeadToHeadMatch, error) {
+	query := `
+	SELECT m.league_id, l.season, m.week, ht.name, at.name, m.home_goals, m.away_goals
+	FROM matches m
+	JOIN leagues l ON m.league_id = l.id
+	JOIN teams ht ON m.home_team_id = ht.id
+	JOIN teams at ON m.away_team_id = at.id
+	WHERE m.is_played = TRUE
+		AND m.league_id != ?
+		AND ((ht.name = ? AND at.name = ?) OR (ht.name = ? AND at.name = ?))
+	ORDER BY m.league_id, m.week`
+
+	rows, err := d.db.Query(query, excludeLeagueID, teamA, teamB, teamB, teamA)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []HeadToHeadMatch
+	for rows.Next() {
+		var m HeadToHeadMatch
+		if err := rows.Scan(&m.LeagueID, &m.Season, &m.Week, &m.HomeTeam, &m.AwayTeam, &m.HomeGoals, &m.AwayGoals); err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+// replace the week by week table saved for a league, an edit to an early week can move everyone after it
+func (d *Database) SaveLeaguePositions(leagueID int64, history []WeekStandings) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM league_positions WHERE league_id = ?", leagueID); err != nil {
+		return err
+	}
+
+	insertQuery := `
+	INSERT INTO league_positions (league_id, week, team_id, position, points)
+	SELECT ?, ?, id, ?, ? FROM teams WHERE name = ?`
+	for _, standings := range history {
+		for teamName, position := range standings.Positions {
+			_, err := tx.Exec(insertQuery, leagueID, standings.Week, position, standings.Points[teamName], teamName)
+			if err != nil {
+				return fmt.Errorf("failed to save league position: %v", err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// save a league's simulation settings, replacing what it had
+func (d *Database) SaveLeagueSettings(leagueID int64, s SimulationSettings) error {
+	query := `
+	INSERT INTO league_settings
+	(league_id, match_model, draw_chance, max_winning_goals, average_goals, form_weight, form_cap, simulations,
+	 play_all_delay_ms, max_goals, points_win, points_draw, points_loss, goals_bonus, goals_bonus_at, losing_bonus,
+	 shootouts, shootout_win, shootout_loss, tie_breakers, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	ON CONFLICT(league_id) DO UPDATE SET
+		match_model = excluded.match_model,
+		draw_chance = excluded.draw_chance,
+		max_winning_goals = excluded.max_winning_goals,
+		average_goals = excluded.average_goals,
+		form_weight = excluded.form_weight,
+		form_cap = excluded.form_cap,
+		simulations = excluded.simulations,
+		play_all_delay_ms = excluded.play_all_delay_ms,
+		max_goals = excluded.max_goals,
+		points_win = excluded.points_win,
+		points_draw = excluded.points_draw,
+		points_loss = excluded.points_loss,
+		goals_bonus = excluded.goals_bonus,
+		goals_bonus_at = excluded.goals_bonus_at,
+		losing_bonus = excluded.losing_bonus,
+		shootouts = excluded.shootouts,
+		shootout_win = excluded.shootout_win,
+		shootout_loss = excluded.shootout_loss,
+		tie_breakers = excluded.tie_breakers,
+		updated_at = CURRENT_TIMESTAMP`
+
+	_, err := d.db.Exec(query, leagueID, s.MatchModel, s.DrawChance, s.MaxWinningGoals, s.AverageGoals, s.FormWeight, s.FormCap,
+		s.Simulations, s.PlayAllDelayMS, s.MaxGoals, s.Points.Win, s.Points.Draw, s.Points.Loss, s.Points.GoalsBonus, s.Points.GoalsBonusAt,
+		s.Points.LosingBonus, s.Points.Shootouts, s.Points.ShootoutWin, s.Points.ShootoutLoss, strings.Join(s.TieBreakers, ","))
+	return err
+}
+
+// get a league's simulation settings, nil if it doesn't have any saved
+func (d *Database) GetLeagueSettings(leagueID int64) (*SimulationSettings, error) {
+	query := `
+	SELECT match_model, draw_chance, max_winning_goals, average_goals, form_weight, form_cap, simulations,
+		play_all_delay_ms, max_goals, points_win, points_draw, points_loss, goals_bonus, goals_bonus_at, losing_bonus,
+		shootouts, shootout_win, shootout_loss, tie_breakers
+	FROM league_settings
+	WHERE league_id = ?`
+
+	var s SimulationSettings
+	var tieBreakers string
+	err := d.db.QueryRow(query, leagueID).Scan(&s.MatchModel, &s.DrawChance, &s.MaxWinningGoals, &s.AverageGoals, &s.FormWeight, &s.FormCap,
+		&s.Simulations, &s.PlayAllDelayMS, &s.MaxGoals, &s.Points.Win, &s.Points.Draw, &s.Points.Loss, &s.Points.GoalsBonus, &s.Points.GoalsBonusAt,
+		&s.Points.LosingBonus, &s.Points.Shootouts, &s.Points.ShootoutWin, &s.Points.ShootoutLoss, &tieBreakers)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tieBreakers != "" {
+		s.TieBreakers = strings.Split(tieBreakers, ",")
+	}
+	if err := s.validate(); err != nil {
+		return nil, fmt.Errorf("league %d has bad settings: %v", leagueID, err)
+	}
+	return &s, nil
+}
+
+// find a predictor by name, creating them if they don't exist yet
+func (d *Database) GetOrCreatePredictor(name string) (*Predictor, error) {
+	if name == "" {
+		return nil, fmt.Errorf("invalid predictor: name is required")
+	}
+
+	if _, err := d.db.Exec("INSERT OR IGNORE INTO predictors (name) VALUES (?)", name); err != nil {
+		return nil, fmt.Errorf("failed to save predictor %s: %v", name, err)
+	}
+
+	predictor := &Predictor{Name: name}
+	err := d.db.QueryRow("SELECT id FROM predictors WHERE name = ?", name).Scan(&predictor.ID)
+	if err != nil {
+		return nil, err
+	}
+	return predictor, nil
+}
+
+// get everyone who has signed up for the predictor league
+func (d *Database) GetPredictors() ([]Predictor, error) {
+	rows, err := d.db.Query("SELECT id, name FROM predictors ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var predictors []Predictor
+	for rows.Next() {
+		var p Predictor
+		if err := rows.Scan(&p.ID, &p.Name); err != nil {
+			return nil, err
+		}
+		predictors = append(predictors, p)
+	}
+	return predictors, rows.Err()
+}
+
+// save or overwrite a predicted score for a fixture
+func (d *Database) SavePrediction(leagueID int64, prediction *Prediction) error {
+	homeTeamID, err := d.getTeamID(prediction.HomeTeam)
+	if err != nil {
+		return err
+	}
+
+	awayTeamID, err := d.getTeamID(prediction.AwayTeam)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	INSERT INTO predictions
+	(league_id, predictor_id, week, home_team_id, away_team_id, home_goals, away_goals, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	ON CONFLICT(league_id, predictor_id, week, home_team_id, away_team_id) DO UPDATE SET
+		home_goals = excluded.home_goals,
+		away_goals = excluded.away_goals,
+		updated_at = CURRENT_TIMESTAMP`
+
+	_, err = d.db.Exec(query, leagueID, prediction.PredictorID, prediction.Week,
+		homeTeamID, awayTeamID, prediction.HomeGoals, prediction.AwayGoals)
+	return err
+}
+
+// get all predictions made for a league
+func (d *Database) GetPredictions(leagueID int64) ([]Prediction, error) {
+	query := `
+	SELECT p.predictor_id, p.week, ht.name, at.name, p.home_goals, p.away_goals
+	FROM predictions p
+	JOIN teams ht ON p.home_team_id = ht.id
+	JOIN teams at ON p.away_team_id = at.id
+	WHERE p.league_id = ?
+	ORDER BY p.week, p.id`
+
+	rows, err := d.db.Query(query, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var predictions []Prediction
+	for rows.Next() {
+		var p Prediction
+		err := rows.Scan(&p.PredictorID, &p.Week, &p.HomeTeam, &p.AwayTeam, &p.HomeGoals, &p.AwayGoals)
+		if err != nil {
+			return nil, err
+		}
+		predictions = append(predictions, p)
+	}
+	return predictions, rows.Err()
+}
+
+// add a change to the league's undo history. anything waiting to be redone
+// is thrown away, the same as it is in memory
+func (d *Database) SaveHistoryEntry(leagueID int64, entry *HistoryEntry) error {
+	before, err := json.Marshal(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := json.Marshal(entry.After)
+	if err != nil {
+		return err
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM league_history WHERE league_id = ? AND undone = TRUE", leagueID); err != nil {
+		return err
+	}
+
+	query := `
+	INSERT INTO league_history
+	(league_id, seq, action, description, week_before, week_after, before_state, after_state, undone)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, FALSE)`
+	_, err = tx.Exec(query, leagueID, entry.Seq, entry.Action, entry.Description,
+		entry.WeekBefore, entry.WeekAfter, string(before), string(after))
+	if err != nil {
+		return fmt.Errorf("failed to save history: %v", err)
+	}
+
+	return tx.Commit()
+}
+
+// move a history entry between the undo and redo stacks
+func (d *Database) SetHistoryUndone(leagueID int64, seq int, undone bool) error {
+	query := "UPDATE league_history SET undone = ? WHERE league_id = ? AND seq = ?"
+	_, err := d.db.Exec(query, undone, leagueID, seq)
+	return err
+}
+
+// get a league's undo history, oldest first
+func (d *Database) GetHistory(leagueID int64) ([]*HistoryEntry, error) {
+	query := `
+	SELECT seq, action, description, week_before, week_after, before_state, after_state, undone
+	FROM league_history
+	WHERE league_id = ?
+	ORDER BY seq`
+
+	rows, err := d.db.Query(query, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []*HistoryEntry
+	for rows.Next() {
+		entry := &HistoryEntry{}
+		var before, after string
+		err := rows.Scan(&entry.Seq, &entry.Action, &entry.Description,
+			&entry.WeekBefore, &entry.WeekAfter, &before, &after, &entry.Undone)
 		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(before), &entry.Before); err != nil {
+			return nil, fmt.Errorf("failed to read history: %v", err)
+		}
+		if err := json.Unmarshal([]byte(after), &entry.After); err != nil {
+			return nil, fmt.Errorf("failed to read history: %v", err)
+		}
+		history = append(history, entry)
+	}
+	return history, rows.Err()
+}
+
+// save a scenario, replacing the pins and tweaks of any scenario with the same name
+func (d *Database) SaveScenario(leagueID int64, scenario *Scenario) (int64, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `
+	INSERT INTO scenarios (league_id, name, updated_at)
+	VALUES (?, ?, CURRENT_TIMESTAMP)
+	ON CONFLICT(league_id, name) DO UPDATE SET updated_at = CURRENT_TIMESTAMP`
+	if _, err := tx.Exec(query, leagueID, scenario.Name); err != nil {
+		return 0, fmt.Errorf("failed to save scenario: %v", err)
+	}
+
+	var scenarioID int64
+	err = tx.QueryRow("SELECT id FROM scenarios WHERE league_id = ? AND name = ?", leagueID, scenario.Name).Scan(&scenarioID)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM scenario_pins WHERE scenario_id = ?", scenarioID); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec("DELETE FROM scenario_strengths WHERE scenario_id = ?", scenarioID); err != nil {
+		return 0, err
+	}
+
+	pinQuery := `
+	INSERT INTO scenario_pins (scenario_id, week, home_team_id, away_team_id, home_goals, away_goals)
+	SELECT ?, ?, ht.id, at.id, ?, ?
+	FROM teams ht, teams at
+	WHERE ht.name = ? AND at.name = ?`
+	for _, pin := range scenario.Pins {
+		_, err := tx.Exec(pinQuery, scenarioID, pin.Week, pin.HomeGoals, pin.AwayGoals, pin.HomeTeam, pin.AwayTeam)
+		if err != nil {
+			return 0, fmt.Errorf("failed to save scenario pin: %v", err)
+		}
+	}
+
+	strengthQuery := `
+	INSERT INTO scenario_strengths (scenario_id, team_id, adjustment)
+	SELECT ?, id, ? FROM teams WHERE name = ?`
+	for teamName, adjustment := range scenario.Strengths {
+		if _, err := tx.Exec(strengthQuery, scenarioID, adjustment, teamName); err != nil {
+			return 0, fmt.Errorf("failed to save scenario adjustment: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	scenario.ID = scenarioID
+	return scenarioID, nil
+}
+
+// get a league's scenarios with their pins and tweaks
+func (d *Database) GetScenarios(leagueID int64) ([]*Scenario, error) {
+	rows, err := d.db.Query("SELECT id, name FROM scenarios WHERE league_id = ? ORDER BY name", leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	var scenarios []*Scenario
+	for rows.Next() {
+		scenario := &Scenario{Strengths: make(map[string]int)}
+		if err := rows.Scan(&scenario.ID, &scenario.Name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		scenarios = append(scenarios, scenario)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	pinQuery := `
+	SELECT sp.week, ht.name, at.name, sp.home_goals, sp.away_goals
+	FROM scenario_pins sp
+	JOIN teams ht ON sp.home_team_id = ht.id
+	JOIN teams at ON sp.away_team_id = at.id
+	WHERE sp.scenario_id = ?
+	ORDER BY sp.week, sp.id`
+	strengthQuery := `
+	SELECT t.name, ss.adjustment
+	FROM scenario_strengths ss
+	JOIN teams t ON ss.team_id = t.id
+	WHERE ss.scenario_id = ?`
+
+	for _, scenario := range scenarios {
+		pinRows, err := d.db.Query(pinQuery, scenario.ID)
+		if err != nil {
+			return nil, err
+		}
+		for pinRows.Next() {
+			var pin ScenarioPin
+			if err := pinRows.Scan(&pin.Week, &pin.HomeTeam, &pin.AwayTeam, &pin.HomeGoals, &pin.AwayGoals); err != nil {
+				pinRows.Close()
+				return nil, err
+			}
+			scenario.Pins = append(scenario.Pins, pin)
+		}
+		pinRows.Close()
+
+		strengthRows, err := d.db.Query(strengthQuery, scenario.ID)
+		if err != nil {
+			return nil, err
+		}
+		for strengthRows.Next() {
+			var teamName string
+			var adjustment int
+			if err := strengthRows.Scan(&teamName, &adjustment); err != nil {
+				strengthRows.Close()
+				return nil, err
+			}
+			scenario.Strengths[teamName] = adjustment
+		}
+		strengthRows.Close()
+	}
+
+	return scenarios, nil
+}
+
+// delete a scenario along with its pins and tweaks
+func (d *Database) DeleteScenario(scenarioID int64) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{
+		"DELETE FROM scenario_pins WHERE scenario_id = ?",
+		"DELETE FROM scenario_strengths WHERE scenario_id = ?",
+		"DELETE FROM scenarios WHERE id = ?",
+	} {
+		if _, err := tx.Exec(query, scenarioID); err != nil {
 			return err
 		}
 	}
+	return tx.Commit()
+}
+
+// save a points adjustment for a league, returns its id
+func (d *Database) SavePointsAdjustment(leagueID int64, adjustment PointsAdjustment) (int64, error) {
+	teamID, err := d.getTeamID(adjustment.Team)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+	INSERT INTO points_adjustments (league_id, team_id, week, points, reason, adjustment_date)
+	VALUES (?, ?, ?, ?, ?, ?)`
+	result, err := d.db.Exec(query, leagueID, teamID, adjustment.Week, adjustment.Points, adjustment.Reason,
+		adjustment.Date.Format(adjustmentDateFormat))
+	if err != nil {
+		return 0, fmt.Errorf("failed to save points adjustment: %v", err)
+	}
+	return result.LastInsertId()
+}
+
+// get a league's points adjustments in the order they were made
+func (d *Database) GetPointsAdjustments(leagueID int64) ([]PointsAdjustment, error) {
+	query := `
+	SELECT pa.id, t.name, pa.week, pa.points, pa.reason, pa.adjustment_date
+	FROM points_adjustments pa
+	JOIN teams t ON pa.team_id = t.id
+	WHERE pa.league_id = ?
+	ORDER BY pa.week, pa.id`
+
+	rows, err := d.db.Query(query, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
+	var adjustments []PointsAdjustment
+	for rows.Next() {
+		var a PointsAdjustment
+		var date string
+		if err := rows.Scan(&a.ID, &a.Team, &a.Week, &a.Points, &a.Reason, &date); err != nil {
+			return nil, err
+		}
+		// sqlite hands a DATE column back as a full timestamp
+		if len(date) > len(adjustmentDateFormat) {
+			date = date[:len(adjustmentDateFormat)]
+		}
+		if a.Date, err = time.Parse(adjustmentDateFormat, date); err != nil {
+			return nil, fmt.Errorf("points adjustment %d has a bad date: %v", a.ID, err)
+		}
+		adjustments = append(adjustments, a)
+	}
+	return adjustments, rows.Err()
+}
+
+// GetLeagueRounds gets when each week of a league is played, in week order.
+// a league saved before there were dates has none
+func (d *Database) GetLeagueRounds(leagueID int64) ([]Round, error) {
+	rows, err := d.db.Query("SELECT round_date, midweek FROM league_rounds WHERE league_id = ? ORDER BY week", leagueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rounds []Round
+	for rows.Next() {
+		var round Round
+		if err := rows.Scan(&round.Date, &round.Midweek); err != nil {
+			return nil, err
+		}
+		rounds = append(rounds, round)
+	}
+	return rounds, rows.Err()
+}
+
+// SaveLeagueCalendar keeps the calendar a league's dates were set from
+func (d *Database) SaveLeagueCalendar(leagueID int64, c CalendarConfig) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	_, err = d.db.Exec("INSERT OR REPLACE INTO league_calendars (league_id, calendar) VALUES (?, ?)", leagueID, string(data))
+	if err != nil {
+		return fmt.Errorf("failed to save the calendar: %v", err)
+	}
 	return nil
 }
 
+// GetLeagueCalendar gets the calendar a league's dates were set from, nil if it wasn't saved
+func (d *Database) GetLeagueCalendar(leagueID int64) (*CalendarConfig, error) {
+	var data string
+	err := d.db.QueryRow("SELECT calendar FROM league_calendars WHERE league_id = ?", leagueID).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var c CalendarConfig
+	if err := json.Unmarshal([]byte(data), &c); err != nil {
+		return nil, fmt.Errorf("league %d has a bad calendar: %v", leagueID, err)
+	}
+	return &c, nil
+}
+
+// delete a points adjustment
+func (d *Database) DeletePointsAdjustment(adjustmentID int64) error {
+	_, err := d.db.Exec("DELETE FROM points_adjustments WHERE id = ?", adjustmentID)
+	return err
+}
+
+// save a squad player, keeping the existing row if they're already there
+func (d *Database) SavePlayer(teamID int64, player *Player) error {
+	query := `
+	INSERT INTO players (team_id, name, position, rating)
+	VALUES (?, ?, ?, ?)
+	ON CONFLICT(team_id, name) DO UPDATE SET
+		position = excluded.position,
+		rating = excluded.rating`
+
+	_, err := d.db.Exec(query, teamID, player.Name, player.Position, player.Rating)
+	if err != nil {
+		return fmt.Errorf("failed to save player %s: %v", player.Name, err)
+	}
+	return nil
+}
+
+// save a player's goals and assists for a league
+func (d *Database) SavePlayerStats(leagueID int64, player *Player) error {
+	query := `
+	INSERT INTO player_stats (league_id, player_id, goals, assists, updated_at)
+	SELECT ?, p.id, ?, ?, CURRENT_TIMESTAMP
+	FROM players p
+	JOIN teams t ON p.team_id = t.id
+	WHERE t.name = ? AND p.name = ?
+	ON CONFLICT(league_id, player_id) DO UPDATE SET
+		goals = excluded.goals,
+		assists = excluded.assists,
+		updated_at = CURRENT_TIMESTAMP`
+
+	_, err := d.db.Exec(query, leagueID, player.Goals, player.Assists, player.TeamName, player.Name)
+	return err
+}
+
+// get the player stats for a league, top scorers first
+func (d *Database) GetPlayerStats(leagueID int64) ([]*Player, error) {
+	query := `
+	SELECT p.name, t.name, p.position, p.rating, ps.goals, ps.assists
+	FROM player_stats ps
+	JOIN players p ON ps.player_id = p.id
+	JOIN teams t ON p.team_id = t.id
+	WHERE ps.league_id = ?
+	ORDER BY ps.goals DESC, ps.assists DESC, p.name`
+
+	rows, err := d.db.Query(query, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var players []*Player
+	for rows.Next() {
+		p := &Player{}
+		err := rows.Scan(&p.Name, &p.TeamName, &p.Position, &p.Rating, &p.Goals, &p.Assists)
+		if err != nil {
+			return nil, err
+		}
+		players = append(players, p)
+	}
+	return players, rows.Err()
+}
+
 // getTeamID gets a team's ID by name
 func (d *Database) getTeamID(teamName string) (int64, error) {
 	var teamID int64
@@ -277,7 +1459,8 @@ func (d *Database) GetLeagueStandings(leagueID int64) ([]*Team, error) {
 // get all matches for a league organized by week
 func (d *Database) GetLeagueMatches(leagueID int64) ([][]Match, error) {
 	query := `
-	SELECT m.week, ht.name, at.name, m.home_goals, m.away_goals, m.is_played, m.is_fixed
+	SELECT m.week, ht.name, at.name, m.home_goals, m.away_goals, m.home_penalties, m.away_penalties, m.is_played, m.is_fixed,
+		COALESCE(m.status, ''), COALESCE(m.play_week, 0), COALESCE(m.midweek, 0), m.match_date
 	FROM matches m
 	JOIN teams ht ON m.home_team_id = ht.id
 	JOIN teams at ON m.away_team_id = at.id
@@ -296,23 +1479,33 @@ func (d *Database) GetLeagueMatches(leagueID int64) ([][]Match, error) {
 	for rows.Next() {
 		var week int
 		var homeTeamName, awayTeamName string
-		var homeGoals, awayGoals int
-		var isPlayed, isFixed bool
+		var homeGoals, awayGoals, homePenalties, awayPenalties int
+		var isPlayed, isFixed, midweek bool
+		var status string
+		var playWeek int
+		var kickoff sql.NullTime
 
 		err := rows.Scan(&week, &homeTeamName, &awayTeamName,
-			&homeGoals, &awayGoals, &isPlayed, &isFixed)
+			&homeGoals, &awayGoals, &homePenalties, &awayPenalties, &isPlayed, &isFixed,
+			&status, &playWeek, &midweek, &kickoff)
 		if err != nil {
 			return nil, err
 		}
 
 		match := Match{
-			HomeTeam:  &Team{Name: homeTeamName},
-			AwayTeam:  &Team{Name: awayTeamName},
-			HomeGoals: homeGoals,
-			AwayGoals: awayGoals,
-			IsPlayed:  isPlayed,
-			IsFixed:   isFixed,
-			Week:      week,
+			HomeTeam:      &Team{Name: homeTeamName},
+			AwayTeam:      &Team{Name: awayTeamName},
+			HomeGoals:     homeGoals,
+			AwayGoals:     awayGoals,
+			HomePenalties: homePenalties,
+			AwayPenalties: awayPenalties,
+			IsPlayed:      isPlayed,
+			IsFixed:       isFixed,
+			Week:          week,
+			Status:        status,
+			PlayWeek:      playWeek,
+			Midweek:       midweek,
+			Kickoff:       kickoff.Time,
 		}
 
 		matchesByWeek[week] = append(matchesByWeek[week], match)
@@ -321,9 +1514,9 @@ func (d *Database) GetLeagueMatches(leagueID int64) ([][]Match, error) {
 		}
 	}
 
-	// turn the map into ordered weeks and make sure we have all 18 weeks
+	// turn the map into ordered weeks, up to the last one with a match in it
 	var fixtures [][]Match
-	for week := 1; week <= 18; week++ {
+	for week := 1; week <= maxWeek; week++ {
 		if matches, exists := matchesByWeek[week]; exists {
 			fixtures = append(fixtures, matches)
 		} else {
@@ -345,6 +1538,10 @@ func (d *Database) Close() error {
 
 // helper functions to make life easier
 func getShortName(fullName string) string {
+	if team, ok := leagueConfig.team(fullName); ok && team.ShortName != "" {
+		return team.ShortName
+	}
+
 	shortNames := map[string]string{
 		"Manchester City":   "MCI",
 		"Arsenal":           "ARS",
@@ -361,6 +1558,9 @@ func getShortName(fullName string) string {
 	if short, exists := shortNames[fullName]; exists {
 		return short
 	}
+	if len(fullName) < 3 {
+		return fullName
+	}
 	return fullName[:3] // just use first 3 characters if we don't have a mapping
 }
 
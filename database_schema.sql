@@ -45,9 +45,14 @@ CREATE TABLE IF NOT EXISTS matches (
     away_team_id INTEGER NOT NULL,
     home_goals INTEGER DEFAULT 0,
     away_goals INTEGER DEFAULT 0,
+    home_penalties INTEGER DEFAULT 0, -- a level match's shootout, both 0 if there wasn't one
+    away_penalties INTEGER DEFAULT 0,
     is_played BOOLEAN DEFAULT FALSE,
     is_fixed BOOLEAN DEFAULT FALSE,
-    match_date DATETIME,
+    status VARCHAR(20) DEFAULT '',    -- postponed, abandoned or walkover, blank as scheduled
+    play_week INTEGER DEFAULT 0,      -- the week a rearranged match is now played in, 0 for its own
+    midweek BOOLEAN DEFAULT FALSE,    -- rearranged into the midweek before play_week's round
+    match_date DATETIME,              -- kickoff
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (league_id) REFERENCES leagues(id),
@@ -61,6 +66,7 @@ CREATE TABLE IF NOT EXISTS league_teams (
     league_id INTEGER NOT NULL,
     team_id INTEGER NOT NULL,
     position INTEGER DEFAULT 0,
+    base_strength INTEGER,               -- the team's strength in this league, it can be changed before kickoff
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (league_id) REFERENCES leagues(id),
     FOREIGN KEY (team_id) REFERENCES teams(id),
@@ -74,11 +80,203 @@ CREATE TABLE IF NOT EXISTS championship_probabilities (
     team_id INTEGER NOT NULL,
     week INTEGER NOT NULL,
     probability REAL NOT NULL,
+    expected_position REAL, -- average finishing position
     calculated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (league_id) REFERENCES leagues(id),
     FOREIGN KEY (team_id) REFERENCES teams(id)
 );
 
+-- Predictors table - people taking part in the predictor league
+CREATE TABLE IF NOT EXISTS predictors (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Predictions table - predicted scores, one per predictor per fixture
+CREATE TABLE IF NOT EXISTS predictions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    league_id INTEGER NOT NULL,
+    predictor_id INTEGER NOT NULL,
+    week INTEGER NOT NULL,
+    home_team_id INTEGER NOT NULL,
+    away_team_id INTEGER NOT NULL,
+    home_goals INTEGER NOT NULL,
+    away_goals INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (league_id) REFERENCES leagues(id),
+    FOREIGN KEY (predictor_id) REFERENCES predictors(id),
+    FOREIGN KEY (home_team_id) REFERENCES teams(id),
+    FOREIGN KEY (away_team_id) REFERENCES teams(id),
+    UNIQUE(league_id, predictor_id, week, home_team_id, away_team_id)
+);
+
+-- Players table - squad players for each team
+CREATE TABLE IF NOT EXISTS players (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    team_id INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    position VARCHAR(3) NOT NULL, -- GK, DEF, MID, FWD
+    rating INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_id) REFERENCES teams(id),
+    UNIQUE(team_id, name)
+);
+
+-- Player stats table - goals and assists per player per league
+CREATE TABLE IF NOT EXISTS player_stats (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    league_id INTEGER NOT NULL,
+    player_id INTEGER NOT NULL,
+    goals INTEGER DEFAULT 0,
+    assists INTEGER DEFAULT 0,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (league_id) REFERENCES leagues(id),
+    FOREIGN KEY (player_id) REFERENCES players(id),
+    UNIQUE(league_id, player_id)
+);
+
+-- Match events table - everything that happened in a match, minute by minute
+CREATE TABLE IF NOT EXISTS match_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    match_id INTEGER NOT NULL,
+    minute INTEGER NOT NULL,
+    event_type VARCHAR(10) NOT NULL, -- goal, yellow, red, sub, injury
+    team_id INTEGER NOT NULL,
+    player_id INTEGER,
+    other_player_id INTEGER, -- assist, or the player coming on
+    weeks_out INTEGER DEFAULT 0, -- injuries only
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (match_id) REFERENCES matches(id),
+    FOREIGN KEY (team_id) REFERENCES teams(id),
+    FOREIGN KEY (player_id) REFERENCES players(id),
+    FOREIGN KEY (other_player_id) REFERENCES players(id)
+);
+
+-- League history table - undoable changes, with the matches they touched as JSON before and after
+CREATE TABLE IF NOT EXISTS league_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    league_id INTEGER NOT NULL,
+    seq INTEGER NOT NULL,
+    action VARCHAR(10) NOT NULL, -- simulate, edit, clear
+    description VARCHAR(200) NOT NULL,
+    week_before INTEGER NOT NULL,
+    week_after INTEGER NOT NULL,
+    before_state TEXT NOT NULL,
+    after_state TEXT NOT NULL,
+    undone BOOLEAN DEFAULT FALSE, -- on the redo stack
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (league_id) REFERENCES leagues(id),
+    UNIQUE(league_id, seq)
+);
+
+-- Scenarios table - named what-if scenarios for a league
+CREATE TABLE IF NOT EXISTS scenarios (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    league_id INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (league_id) REFERENCES leagues(id),
+    UNIQUE(league_id, name)
+);
+
+-- Scenario pins table - results pinned by a scenario
+CREATE TABLE IF NOT EXISTS scenario_pins (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    scenario_id INTEGER NOT NULL,
+    week INTEGER NOT NULL,
+    home_team_id INTEGER NOT NULL,
+    away_team_id INTEGER NOT NULL,
+    home_goals INTEGER NOT NULL,
+    away_goals INTEGER NOT NULL,
+    FOREIGN KEY (scenario_id) REFERENCES scenarios(id),
+    FOREIGN KEY (home_team_id) REFERENCES teams(id),
+    FOREIGN KEY (away_team_id) REFERENCES teams(id)
+);
+
+-- Scenario strengths table - team strength tweaks in a scenario
+CREATE TABLE IF NOT EXISTS scenario_strengths (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    scenario_id INTEGER NOT NULL,
+    team_id INTEGER NOT NULL,
+    adjustment INTEGER NOT NULL,
+    FOREIGN KEY (scenario_id) REFERENCES scenarios(id),
+    FOREIGN KEY (team_id) REFERENCES teams(id),
+    UNIQUE(scenario_id, team_id)
+);
+
+-- League positions table - the table after each week, for the position chart
+CREATE TABLE IF NOT EXISTS league_positions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    league_id INTEGER NOT NULL,
+    week INTEGER NOT NULL,
+    team_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    points INTEGER NOT NULL,
+    FOREIGN KEY (league_id) REFERENCES leagues(id),
+    FOREIGN KEY (team_id) REFERENCES teams(id),
+    UNIQUE(league_id, week, team_id)
+);
+
+-- League settings table - the numbers each league's simulation runs on
+CREATE TABLE IF NOT EXISTS league_settings (
+    league_id INTEGER PRIMARY KEY,
+    match_model VARCHAR(20) NOT NULL DEFAULT 'strength', -- strength or poisson
+    draw_chance REAL NOT NULL,           -- chance of a draw on top of the home win chance
+    max_winning_goals INTEGER NOT NULL,  -- the winner of a simulated match scores 1 to this many
+    average_goals REAL NOT NULL DEFAULT 2.7, -- poisson: goals in an average match
+    form_weight REAL NOT NULL,           -- strength five straight wins add
+    form_cap REAL NOT NULL,              -- furthest form can move strength from its base
+    simulations INTEGER NOT NULL,        -- monte carlo runs for the probability table
+    play_all_delay_ms INTEGER NOT NULL,  -- gap between weeks in play all at normal speed
+    max_goals INTEGER NOT NULL,          -- most goals a result can be edited to
+    points_win INTEGER NOT NULL DEFAULT 3,
+    points_draw INTEGER NOT NULL DEFAULT 1,
+    points_loss INTEGER NOT NULL DEFAULT 0,
+    goals_bonus INTEGER NOT NULL DEFAULT 0,    -- on top for scoring goals_bonus_at or more
+    goals_bonus_at INTEGER NOT NULL DEFAULT 4,
+    losing_bonus INTEGER NOT NULL DEFAULT 0,   -- on top for losing by a single goal
+    shootouts BOOLEAN NOT NULL DEFAULT 0,      -- no draws, level matches go to penalties
+    shootout_win INTEGER NOT NULL DEFAULT 2,
+    shootout_loss INTEGER NOT NULL DEFAULT 1,
+    tie_breakers VARCHAR(100) NOT NULL DEFAULT 'goal_difference', -- comma separated, in order
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (league_id) REFERENCES leagues(id)
+);
+
+-- League rounds table - when each week of a league is played
+CREATE TABLE IF NOT EXISTS league_rounds (
+    league_id INTEGER NOT NULL,
+    week INTEGER NOT NULL,
+    round_date DATE NOT NULL,            -- the saturday, or the tuesday of a midweek round
+    midweek BOOLEAN DEFAULT FALSE,
+    PRIMARY KEY (league_id, week),
+    FOREIGN KEY (league_id) REFERENCES leagues(id)
+);
+
+-- League calendars table - the calendar a league's dates were set from
+CREATE TABLE IF NOT EXISTS league_calendars (
+    league_id INTEGER PRIMARY KEY,
+    calendar TEXT NOT NULL,              -- the calendar config as json
+    FOREIGN KEY (league_id) REFERENCES leagues(id)
+);
+
+-- Points adjustments table - points the league takes off a team, or gives back
+CREATE TABLE IF NOT EXISTS points_adjustments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    league_id INTEGER NOT NULL,
+    team_id INTEGER NOT NULL,
+    week INTEGER NOT NULL,               -- counts in the table from the end of this week, 0 from the start
+    points INTEGER NOT NULL,             -- negative for a deduction
+    reason VARCHAR(200) NOT NULL,
+    adjustment_date DATE NOT NULL,       -- when it was handed down
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (league_id) REFERENCES leagues(id),
+    FOREIGN KEY (team_id) REFERENCES teams(id)
+);
+
 -- =====================================================
 -- INDEXES FOR PERFORMANCE
 -- =====================================================
@@ -86,12 +284,21 @@ CREATE TABLE IF NOT EXISTS championship_probabilities (
 -- Index on matches for faster league queries
 CREATE INDEX IF NOT EXISTS idx_matches_league_week ON matches(league_id, week);
 
+-- One row per fixture so saving a match again updates it
+CREATE UNIQUE INDEX IF NOT EXISTS idx_matches_fixture ON matches(league_id, week, home_team_id, away_team_id);
+
+-- Index on match events for match reports
+CREATE INDEX IF NOT EXISTS idx_match_events_match ON match_events(match_id);
+
 -- Index on league_teams for faster team lookups
 CREATE INDEX IF NOT EXISTS idx_league_teams_league ON league_teams(league_id);
 
 -- Index on championship probabilities
 CREATE INDEX IF NOT EXISTS idx_probabilities_league_week ON championship_probabilities(league_id, week);
 
+-- Index on points adjustments for loading a league
+CREATE INDEX IF NOT EXISTS idx_points_adjustments_league ON points_adjustments(league_id);
+
 -- Index on teams name for faster lookups
 CREATE INDEX IF NOT EXISTS idx_teams_name ON teams(name);
 
@@ -238,6 +445,7 @@ ORDER BY t.goals_against ASC;
 SELECT 
     cp.week,
     cp.probability,
+    cp.expected_position,
     t.name
 FROM championship_probabilities cp
 JOIN teams t ON cp.team_id = t.id
@@ -287,6 +495,107 @@ JOIN league_teams lt ON t.id = lt.team_id
 WHERE lt.league_id = 1
 ORDER BY t.points DESC;
 
+-- 13. Get predictor leaderboard (3 points for exact score, 1 for correct result)
+SELECT 
+    pr.name,
+    COUNT(*) AS predictions_scored,
+    SUM(CASE 
+        WHEN p.home_goals = m.home_goals AND p.away_goals = m.away_goals THEN 3
+        WHEN (p.home_goals > p.away_goals AND m.home_goals > m.away_goals) OR
+             (p.home_goals = p.away_goals AND m.home_goals = m.away_goals) OR
+             (p.home_goals < p.away_goals AND m.home_goals < m.away_goals) THEN 1
+        ELSE 0
+    END) AS points
+FROM predictions p
+JOIN predictors pr ON p.predictor_id = pr.id
+JOIN matches m ON m.league_id = p.league_id AND m.week = p.week
+    AND m.home_team_id = p.home_team_id AND m.away_team_id = p.away_team_id
+WHERE p.league_id = 1 AND m.is_played = TRUE
+GROUP BY pr.id
+ORDER BY points DESC;
+
+-- 14. Get golden boot standings
+SELECT 
+    p.name,
+    t.name AS team,
+    ps.goals,
+    ps.assists
+FROM player_stats ps
+JOIN players p ON ps.player_id = p.id
+JOIN teams t ON p.team_id = t.id
+WHERE ps.league_id = 1
+ORDER BY ps.goals DESC, ps.assists DESC
+LIMIT 10;
+
+-- 15. Get the timeline for a match
+SELECT 
+    e.minute,
+    e.event_type,
+    t.name AS team,
+    p.name AS player,
+    o.name AS other_player
+FROM match_events e
+JOIN teams t ON e.team_id = t.id
+LEFT JOIN players p ON e.player_id = p.id
+LEFT JOIN players o ON e.other_player_id = o.id
+WHERE e.match_id = 1 -- Replace with actual match ID
+ORDER BY e.minute, e.id;
+
+-- 16. Get a league's undo history, newest first
+SELECT 
+    seq,
+    action,
+    description,
+    week_before,
+    week_after,
+    CASE WHEN undone THEN 'redo' ELSE 'undo' END AS stack
+FROM league_history
+WHERE league_id = 1
+ORDER BY seq DESC;
+
+-- 17. Get a league's scenarios with their pinned results
+SELECT 
+    s.name AS scenario,
+    sp.week,
+    ht.name AS home_team,
+    sp.home_goals,
+    sp.away_goals,
+    at.name AS away_team
+FROM scenarios s
+JOIN scenario_pins sp ON sp.scenario_id = s.id
+JOIN teams ht ON sp.home_team_id = ht.id
+JOIN teams at ON sp.away_team_id = at.id
+WHERE s.league_id = 1
+ORDER BY s.name, sp.week;
+
+-- 18. Get every team's league position week by week
+SELECT 
+    lp.week,
+    t.name,
+    lp.position,
+    lp.points
+FROM league_positions lp
+JOIN teams t ON lp.team_id = t.id
+WHERE lp.league_id = 1
+ORDER BY lp.week, lp.position;
+
+-- 19. Get every meeting between two teams in earlier seasons
+SELECT 
+    l.season,
+    m.week,
+    ht.name AS home_team,
+    m.home_goals,
+    m.away_goals,
+    at.name AS away_team
+FROM matches m
+JOIN leagues l ON m.league_id = l.id
+JOIN teams ht ON m.home_team_id = ht.id
+JOIN teams at ON m.away_team_id = at.id
+WHERE m.is_played = TRUE
+    AND m.league_id != 1
+    AND ((ht.name = 'Arsenal' AND at.name = 'Chelsea') OR (ht.name = 'Chelsea' AND at.name = 'Arsenal'))
+ORDER BY m.league_id, m.week;
+
 -- =====================================================
 -- SAMPLE DATA INSERT STATEMENTS
 -- =====================================================
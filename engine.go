@@ -0,0 +1,865 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SeasonEngine owns the league and is the only thing allowed to change it.
+// every operation takes the lock, so the gui and the play all timer can't
+// trip over each other, and subscribers are told once a change is done
+type SeasonEngine struct {
+	mu          sync.RWMutex
+	jobs        sync.RWMutex // held while work is done on a snapshot, the settings only change between jobs
+	league      *League
+	subscribers []func()
+	undoStack   []*HistoryEntry
+	redoStack   []*HistoryEntry
+	nextSeq     int
+}
+
+// create an engine, picking up the last season if there is one to pick up
+func NewSeasonEngine() *SeasonEngine {
+	e := &SeasonEngine{}
+	if err := e.resumeSeason(); err != nil {
+		fmt.Printf("Starting a new season: %v\n", err)
+		e.newSeason(leagueConfig)
+	}
+	return e
+}
+
+// set up a new league from config with its fixtures, caller must hold the lock
+func (e *SeasonEngine) newSeason(config LeagueConfig) {
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng = rand.New(rand.NewSource(seed))
+	settings = config.settings()
+
+	league := NewLeague(config, rng)
+	league.Name = config.Name
+	league.Season = config.Season
+	if league.Season == "" {
+		league.Season = time.Now().Format("2006")
+	}
+	league.Seed = seed
+	// set up fixtures right away
+	league.scheduleFixtures(config.Rounds)
+	if err := league.scheduleDates(config.Calendar); err != nil {
+		fmt.Printf("Failed to set dates: %v\n", err)
+	}
+	league.Week = 0
+
+	// save the league so predictions and results have something to point at
+	if db != nil {
+		id, err := db.RegisterLeague(league, league.Name, league.Season)
+		if err != nil {
+			fmt.Printf("Failed to save league: %v\n", err)
+		} else {
+			league.ID = id
+			if err := db.SaveLeagueSettings(id, settings); err != nil {
+				fmt.Printf("Failed to save settings: %v\n", err)
+			}
+		}
+	}
+	// carry on counting, so nothing worked out for the last league passes for this one
+	if e.league != nil {
+		league.Revision = e.league.Revision + 1
+	}
+	e.league = league
+	e.undoStack = nil
+	e.redoStack = nil
+	e.nextSeq = 1
+
+	// where everyone starts on the trend chart
+	e.recordProbabilities()
+}
+
+// load the most recent league from the database along with its undo history,
+// caller must hold the lock
+func (e *SeasonEngine) resumeSeason() error {
+	if db == nil {
+		return fmt.Errorf("no database")
+	}
+
+	leagueID, week, err := db.GetLatestLeague()
+	if err != nil {
+		return err
+	}
+	teams, err := db.GetLeagueTeams(leagueID)
+	if err != nil {
+		return err
+	}
+	if len(teams) < 2 {
+		return fmt.Errorf("league %d has %d teams", leagueID, len(teams))
+	}
+	for _, team := range teams {
+		team.CurrentStrength = team.BaseStrength
+		team.Form = make([]string, 5)
+		team.Availability = 1.0
+		team.Squad = generateSquad(team)
+	}
+
+	// fixtures come back with teams and players by name only, swap in the real ones
+	fixtures, err := db.GetLeagueMatches(leagueID)
+	if err != nil {
+		return err
+	}
+	if len(fixtures) == 0 {
+		return fmt.Errorf("league %d was saved without its fixtures", leagueID)
+	}
+	name, season, err := db.GetLeagueName(leagueID)
+	if err != nil {
+		return err
+	}
+	league := &League{ID: leagueID, Name: name, Season: season, Teams: teams, Week: week}
+	league.Fixtures = make([][]Match, len(fixtures))
+	for w := range fixtures {
+		for i := range fixtures[w] {
+			saved := &fixtures[w][i]
+			events, err := db.GetMatchEvents(leagueID, saved)
+			if err != nil {
+				return err
+			}
+			saved.Events = events
+
+			home, away := findTeam(teams, saved.HomeTeam.Name), findTeam(teams, saved.AwayTeam.Name)
+			if home == nil || away == nil {
+				return fmt.Errorf("league %d has a fixture for a team that isn't in it", leagueID)
+			}
+			league.Fixtures[w] = append(league.Fixtures[w], Match{HomeTeam: home, AwayTeam: away, Week: w + 1})
+			if _, err := league.restoreMatch(saved.snapshot()); err != nil {
+				return err
+			}
+		}
+	}
+
+	history, err := db.GetHistory(leagueID)
+	if err != nil {
+		return err
+	}
+	league.ProbabilityHistory, err = db.GetProbabilityHistory(leagueID)
+	if err != nil {
+		return err
+	}
+	league.Adjustments, err = db.GetPointsAdjustments(leagueID)
+	if err != nil {
+		return err
+	}
+	league.Rounds, err = db.GetLeagueRounds(leagueID)
+	if err != nil {
+		return err
+	}
+	// leagues saved with dates but not the calendar they came from get the config's
+	calendar, err := db.GetLeagueCalendar(leagueID)
+	if err != nil {
+		return err
+	}
+	if calendar == nil && len(league.Rounds) > 0 {
+		calendar = &leagueConfig.Calendar
+		if err := db.SaveLeagueCalendar(leagueID, *calendar); err != nil {
+			fmt.Printf("Failed to save calendar: %v\n", err)
+		}
+	}
+	if calendar != nil {
+		league.Calendar = *calendar
+	}
+
+	// leagues saved before they had settings get the defaults
+	saved, err := db.GetLeagueSettings(leagueID)
+	if err != nil {
+		return err
+	}
+	if saved == nil {
+		s := leagueConfig.settings()
+		saved = &s
+		if err := db.SaveLeagueSettings(leagueID, s); err != nil {
+			fmt.Printf("Failed to save settings: %v\n", err)
+		}
+	}
+	settings = *saved
+
+	league.RecalculateStats()
+	e.league = league
+	e.undoStack = nil
+	e.redoStack = nil
+	e.nextSeq = 1
+	for _, entry := range history {
+		if entry.Undone {
+			// the earliest undone change is the next one to redo
+			e.redoStack = append([]*HistoryEntry{entry}, e.redoStack...)
+		} else {
+			e.undoStack = append(e.undoStack, entry)
+		}
+		if entry.Seq >= e.nextSeq {
+			e.nextSeq = entry.Seq + 1
+		}
+	}
+
+	// seasons saved before the trend was kept only get it from here on
+	if n := len(league.ProbabilityHistory); n == 0 || league.ProbabilityHistory[n-1].Week != league.weeksPlayed() {
+		e.recordProbabilities()
+	}
+	return nil
+}
+
+// find a team by name
+func findTeam(teams []*Team, name string) *Team {
+	for _, team := range teams {
+		if team.Name == name {
+			return team
+		}
+	}
+	return nil
+}
+
+// Subscribe registers a function to call after every change. it's called
+// from whichever goroutine made the change, without the lock held
+func (e *SeasonEngine) Subscribe(fn func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.subscribers = append(e.subscribers, fn)
+}
+
+// let everyone know the league changed
+func (e *SeasonEngine) notify() {
+	e.mu.RLock()
+	subscribers := make([]func(), len(e.subscribers))
+	copy(subscribers, e.subscribers)
+	e.mu.RUnlock()
+
+	for _, fn := range subscribers {
+		fn()
+	}
+}
+
+// Read gives fn the league to look at. fn must not change it or hold on to it
+func (e *SeasonEngine) Read(fn func(league *League)) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	fn(e.league)
+}
+
+// Snapshot gives fn a copy of the league to work on without holding the lock, for anything
+// slow like thousands of simulated seasons. changes carry on while it runs, apart from
+// the settings, which it still reads and which wait for it to finish
+func (e *SeasonEngine) Snapshot(fn func(league *League)) {
+	e.jobs.RLock()
+	defer e.jobs.RUnlock()
+	var league *League
+	e.Read(func(l *League) {
+		league = l.snapshot()
+	})
+	fn(league)
+}
+
+// LeagueID returns the database id of the current league
+func (e *SeasonEngine) LeagueID() int64 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.league.ID
+}
+
+// Settings returns the current league's simulation settings
+func (e *SeasonEngine) Settings() SimulationSettings {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return settings
+}
+
+// UpdateSettings changes the current league's simulation settings. strengths are
+// worked out again from the results so far, and so are the chances
+func (e *SeasonEngine) UpdateSettings(s SimulationSettings) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+
+	e.jobs.Lock()
+	defer e.jobs.Unlock()
+	e.mu.Lock()
+	settings = s
+	e.league.Revision++
+	if db != nil && e.league.ID != 0 {
+		if err := db.SaveLeagueSettings(e.league.ID, s); err != nil {
+			fmt.Printf("Failed to save settings: %v\n", err)
+		}
+	}
+	e.league.RecalculateStats()
+	e.saveLeaguePositions()
+	e.recordProbabilities()
+	e.mu.Unlock()
+
+	e.notify()
+	return nil
+}
+
+// LeagueConfig returns the config new leagues are set up from
+func (e *SeasonEngine) LeagueConfig() LeagueConfig {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return leagueConfig
+}
+
+// AddPointsAdjustment takes points off a team, or gives them back. the table
+// and the chances are worked out again with it
+func (e *SeasonEngine) AddPointsAdjustment(a PointsAdjustment) error {
+	e.mu.Lock()
+	if err := e.league.validateAdjustment(a); err != nil {
+		e.mu.Unlock()
+		return err
+	}
+	if db != nil && e.league.ID != 0 {
+		id, err := db.SavePointsAdjustment(e.league.ID, a)
+		if err != nil {
+			e.mu.Unlock()
+			return err
+		}
+		a.ID = id
+	} else {
+		a.ID = e.league.unsavedAdjustmentID()
+	}
+	e.league.Adjustments = append(e.league.Adjustments, a)
+	sort.SliceStable(e.league.Adjustments, func(i, j int) bool {
+		return e.league.Adjustments[i].Week < e.league.Adjustments[j].Week
+	})
+	e.adjustmentsChanged()
+	e.mu.Unlock()
+
+	e.notify()
+	return nil
+}
+
+// RemovePointsAdjustment takes an adjustment back off
+func (e *SeasonEngine) RemovePointsAdjustment(id int64) error {
+	e.mu.Lock()
+	index := -1
+	for i, a := range e.league.Adjustments {
+		if a.ID == id {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		e.mu.Unlock()
+		return fmt.Errorf("no points adjustment %d", id)
+	}
+	if db != nil && id > 0 {
+		if err := db.DeletePointsAdjustment(id); err != nil {
+			e.mu.Unlock()
+			return err
+		}
+	}
+	e.league.Adjustments = append(e.league.Adjustments[:index], e.league.Adjustments[index+1:]...)
+	e.adjustmentsChanged()
+	e.mu.Unlock()
+
+	e.notify()
+	return nil
+}
+
+// work the table and chances out again after the adjustments changed, caller must hold the lock
+func (e *SeasonEngine) adjustmentsChanged() {
+	e.league.Revision++
+	e.league.RecalculateStats()
+	e.saveLeaguePositions()
+	e.recordProbabilities()
+}
+
+// SaveDefaultSettings makes s what new leagues start with, writing it to the defaults file
+// next to the league config. returns the file it went to
+func (e *SeasonEngine) SaveDefaultSettings(s SimulationSettings) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	path, err := saveSettingsDefaults(leagueConfigPath, s)
+	if err != nil {
+		return "", err
+	}
+	leagueConfig.setSettings(s)
+	return path, nil
+}
+
+// SimulateWeek plays the next week and returns which week it was,
+// or false if the season is already over
+func (e *SeasonEngine) SimulateWeek() (int, bool) {
+	e.mu.Lock()
+	week, ok := e.simulateWeek()
+	e.mu.Unlock()
+
+	if ok {
+		e.notify()
+	}
+	return week, ok
+}
+
+// SimulateWeekUpTo plays the next week as long as it's no later than lastWeek
+func (e *SeasonEngine) SimulateWeekUpTo(lastWeek int) (int, bool) {
+	e.mu.Lock()
+	if e.league.upcomingWeek() > lastWeek {
+		e.mu.Unlock()
+		return 0, false
+	}
+	week, ok := e.simulateWeek()
+	e.mu.Unlock()
+
+	if ok {
+		e.notify()
+	}
+	return week, ok
+}
+
+// play the current week and move on, caller must hold the lock
+func (e *SeasonEngine) simulateWeek() (int, bool) {
+	l := e.league
+	weekBefore := l.Week
+	if l.Week == 0 {
+		l.Week = 1
+		// fixtures were scheduled up front, only generate them if they're missing
+		if l.Fixtures == nil {
+			l.scheduleFixtures(leagueConfig.Rounds)
+		}
+	}
+
+	// check if we've reached the end of the season
+	if l.Week > len(l.Fixtures) {
+		return 0, false
+	}
+
+	week := l.Week
+	entry := &HistoryEntry{
+		Action:      ActionSimulateWeek,
+		Description: fmt.Sprintf("Week %d", week),
+		WeekBefore:  weekBefore,
+		Before:      l.snapshotWeek(week),
+	}
+	for _, match := range l.playWeek(week) {
+		e.saveMatch(match)
+	}
+
+	l.Week++
+	l.RecalculateStats()
+	e.savePlayerStats()
+
+	entry.WeekAfter = l.Week
+	entry.After = l.snapshotWeek(week)
+	e.record(entry)
+	return week, true
+}
+
+// EditResult overrides the score of a played match, or pins the score of one
+// still to come so it's played out that way
+func (e *SeasonEngine) EditResult(week int, homeTeam, awayTeam string, homeGoals, awayGoals int) error {
+	e.mu.Lock()
+	match := e.league.findMatch(week, homeTeam, awayTeam)
+	if match == nil {
+		e.mu.Unlock()
+		return fmt.Errorf("no match between %s and %s in week %d", homeTeam, awayTeam, week)
+	}
+	if homeGoals < 0 || awayGoals < 0 || homeGoals > settings.MaxGoals || awayGoals > settings.MaxGoals {
+		e.mu.Unlock()
+		return fmt.Errorf("goals have to be between 0 and %d", settings.MaxGoals)
+	}
+
+	entry := &HistoryEntry{
+		Action:      ActionEditResult,
+		Description: fmt.Sprintf("%s %d - %d %s", getShortName(homeTeam), homeGoals, awayGoals, getShortName(awayTeam)),
+		WeekBefore:  e.league.Week,
+		WeekAfter:   e.league.Week,
+		Before:      []MatchSnapshot{match.snapshot()},
+	}
+
+	if match.Status == MatchWalkover {
+		match.Status = "" // it's a result like any other now
+	}
+	if match.dueWeek() >= e.league.upcomingWeek() {
+		// no timeline until the week is played
+		match.HomeGoals = homeGoals
+		match.AwayGoals = awayGoals
+		match.IsFixed = true
+		match.IsPlayed = false
+	} else {
+		// the timeline has to be played again if the score changed
+		scoreChanged := match.HomeGoals != homeGoals || match.AwayGoals != awayGoals
+		if scoreChanged || len(match.Goals) != homeGoals+awayGoals {
+			match.playTimeline(homeGoals, awayGoals)
+		}
+		match.IsFixed = true
+		match.IsPlayed = true
+	}
+	e.saveMatch(match)
+
+	// recalculate all stats
+	e.league.RecalculateStats()
+	e.savePlayerStats()
+
+	entry.After = []MatchSnapshot{match.snapshot()}
+	e.record(entry)
+	e.mu.Unlock()
+
+	e.notify()
+	return nil
+}
+
+// ClearOverride hands a manually fixed result back to the simulation. a match
+// in a week that's been played is simulated again, one that hasn't goes back to unplayed
+func (e *SeasonEngine) ClearOverride(week int, homeTeam, awayTeam string) error {
+	e.mu.Lock()
+	match := e.league.findMatch(week, homeTeam, awayTeam)
+	if match == nil {
+		e.mu.Unlock()
+		return fmt.Errorf("no match between %s and %s in week %d", homeTeam, awayTeam, week)
+	}
+	if !match.IsFixed {
+		e.mu.Unlock()
+		return nil
+	}
+
+	entry := &HistoryEntry{
+		Action:      ActionClearFixed,
+		Description: fmt.Sprintf("clear %s v %s", getShortName(homeTeam), getShortName(awayTeam)),
+		WeekBefore:  e.league.Week,
+		WeekAfter:   e.league.Week,
+		Before:      []MatchSnapshot{match.snapshot()},
+	}
+
+	match.IsFixed = false
+	if match.Status == MatchWalkover {
+		match.Status = "" // back to being played, when it's due
+	}
+	if match.dueWeek() < e.league.Week {
+		match.playMatch()
+	} else {
+		match.HomeGoals = 0
+		match.AwayGoals = 0
+		match.HomePenalties, match.AwayPenalties = 0, 0
+		match.IsPlayed = false
+		match.Events = nil
+		match.collectIncidents()
+	}
+	e.saveMatch(match)
+
+	e.league.RecalculateStats()
+	e.savePlayerStats()
+
+	entry.After = []MatchSnapshot{match.snapshot()}
+	e.record(entry)
+	e.mu.Unlock()
+
+	e.notify()
+	return nil
+}
+
+// PostponeMatch moves a match that hasn't been played to a later week, or that week's midweek
+func (e *SeasonEngine) PostponeMatch(week int, homeTeam, awayTeam string, toWeek int, midweek bool) error {
+	return e.rearrange(week, homeTeam, awayTeam, "postpone", func(match *Match) error {
+		return e.league.postponeMatch(match, toWeek, midweek)
+	})
+}
+
+// AbandonMatch voids a played match's result so it's played again in toWeek
+func (e *SeasonEngine) AbandonMatch(week int, homeTeam, awayTeam string, toWeek int, midweek bool) error {
+	return e.rearrange(week, homeTeam, awayTeam, "abandon", func(match *Match) error {
+		return e.league.abandonMatch(match, toWeek, midweek)
+	})
+}
+
+// AwardWalkover gives a match that hasn't been played to winner without it being played
+func (e *SeasonEngine) AwardWalkover(week int, homeTeam, awayTeam, winner string) error {
+	return e.rearrange(week, homeTeam, awayTeam, "walkover", func(match *Match) error {
+		return e.league.awardWalkover(match, winner)
+	})
+}
+
+// change a match with change and record it so it can be undone
+func (e *SeasonEngine) rearrange(week int, homeTeam, awayTeam, what string, change func(match *Match) error) error {
+	e.mu.Lock()
+	match := e.league.findMatch(week, homeTeam, awayTeam)
+	if match == nil {
+		e.mu.Unlock()
+		return fmt.Errorf("no match between %s and %s in week %d", homeTeam, awayTeam, week)
+	}
+
+	entry := &HistoryEntry{
+		Action:      ActionRearrange,
+		Description: fmt.Sprintf("%s %s v %s", what, getShortName(homeTeam), getShortName(awayTeam)),
+		WeekBefore:  e.league.Week,
+		WeekAfter:   e.league.Week,
+		Before:      []MatchSnapshot{match.snapshot()},
+	}
+	if err := change(match); err != nil {
+		e.mu.Unlock()
+		return err
+	}
+	e.saveMatch(match)
+
+	e.league.RecalculateStats()
+	e.savePlayerStats()
+
+	entry.After = []MatchSnapshot{match.snapshot()}
+	e.record(entry)
+	e.mu.Unlock()
+
+	e.notify()
+	return nil
+}
+
+// StartLeague throws the current season away for a new league set up from config
+func (e *SeasonEngine) StartLeague(config LeagueConfig) error {
+	if err := config.validate(); err != nil {
+		return err
+	}
+
+	e.jobs.Lock()
+	defer e.jobs.Unlock()
+	e.mu.Lock()
+	e.newSeason(config)
+	e.mu.Unlock()
+
+	e.notify()
+	return nil
+}
+
+// NextSeason starts the next season with the same teams at the same strengths
+func (e *SeasonEngine) NextSeason() {
+	e.jobs.Lock()
+	defer e.jobs.Unlock()
+	e.mu.Lock()
+	e.newSeason(e.league.nextSeasonConfig())
+	e.mu.Unlock()
+
+	e.notify()
+}
+
+// Undo takes back the last week simulated or result edited
+func (e *SeasonEngine) Undo() error {
+	e.mu.Lock()
+	if len(e.undoStack) == 0 {
+		e.mu.Unlock()
+		return nil
+	}
+	entry := e.undoStack[len(e.undoStack)-1]
+	if err := e.apply(entry.Before, entry.WeekBefore); err != nil {
+		e.mu.Unlock()
+		return err
+	}
+	e.undoStack = e.undoStack[:len(e.undoStack)-1]
+	e.redoStack = append(e.redoStack, entry)
+	e.saveHistoryUndone(entry, true)
+	e.mu.Unlock()
+
+	e.notify()
+	return nil
+}
+
+// Redo puts back the last change that was undone
+func (e *SeasonEngine) Redo() error {
+	e.mu.Lock()
+	if len(e.redoStack) == 0 {
+		e.mu.Unlock()
+		return nil
+	}
+	entry := e.redoStack[len(e.redoStack)-1]
+	if err := e.apply(entry.After, entry.WeekAfter); err != nil {
+		e.mu.Unlock()
+		return err
+	}
+	e.redoStack = e.redoStack[:len(e.redoStack)-1]
+	e.undoStack = append(e.undoStack, entry)
+	e.saveHistoryUndone(entry, false)
+	e.mu.Unlock()
+
+	e.notify()
+	return nil
+}
+
+// HistoryDescriptions says what undo and redo would do next, "" if nothing
+func (e *SeasonEngine) HistoryDescriptions() (string, string) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	undo, redo := "", ""
+	if len(e.undoStack) > 0 {
+		undo = e.undoStack[len(e.undoStack)-1].Description
+	}
+	if len(e.redoStack) > 0 {
+		redo = e.redoStack[len(e.redoStack)-1].Description
+	}
+	return undo, redo
+}
+
+// put matches back the way the snapshots have them, caller must hold the lock
+func (e *SeasonEngine) apply(snapshots []MatchSnapshot, week int) error {
+	var changed []*Match
+	for _, s := range snapshots {
+		match, err := e.league.restoreMatch(s)
+		if err != nil {
+			return err
+		}
+		changed = append(changed, match)
+	}
+	e.league.Week = week
+	e.league.Revision++
+	e.league.RecalculateStats()
+
+	for _, match := range changed {
+		e.saveMatch(match)
+	}
+	e.savePlayerStats()
+	e.saveLeagueWeek()
+	e.saveLeaguePositions()
+	e.recordProbabilities()
+	return nil
+}
+
+// push a new change onto the undo stack, which means nothing can be redone now.
+// caller must hold the lock
+func (e *SeasonEngine) record(entry *HistoryEntry) {
+	entry.Seq = e.nextSeq
+	e.nextSeq++
+	e.league.Revision++
+	e.undoStack = append(e.undoStack, entry)
+	e.redoStack = nil
+	e.saveLeagueWeek()
+	e.saveLeaguePositions()
+	e.recordProbabilities()
+
+	if db == nil || e.league.ID == 0 {
+		return
+	}
+	if err := db.SaveHistoryEntry(e.league.ID, entry); err != nil {
+		fmt.Printf("Failed to save history: %v\n", err)
+	}
+}
+
+// mark a history entry as undone or not, caller must hold the lock
+func (e *SeasonEngine) saveHistoryUndone(entry *HistoryEntry, undone bool) {
+	entry.Undone = undone
+	if db == nil || e.league.ID == 0 {
+		return
+	}
+	if err := db.SetHistoryUndone(e.league.ID, entry.Seq, undone); err != nil {
+		fmt.Printf("Failed to save history: %v\n", err)
+	}
+}
+
+// save how far through the season we are, caller must hold the lock
+func (e *SeasonEngine) saveLeagueWeek() {
+	if db == nil || e.league.ID == 0 {
+		return
+	}
+	completed := e.league.Week > len(e.league.Fixtures)
+	if err := db.UpdateLeagueWeek(e.league.ID, e.league.Week, completed); err != nil {
+		fmt.Printf("Failed to save league: %v\n", err)
+	}
+}
+
+// save where every team stood after each week, caller must hold the lock
+func (e *SeasonEngine) saveLeaguePositions() {
+	if db == nil || e.league.ID == 0 {
+		return
+	}
+	if err := db.SaveLeaguePositions(e.league.ID, e.league.PositionHistory); err != nil {
+		fmt.Printf("Failed to save league positions: %v\n", err)
+	}
+}
+
+// save a single match and its timeline, caller must hold the lock
+func (e *SeasonEngine) saveMatch(match *Match) {
+	if db == nil || e.league.ID == 0 {
+		return
+	}
+	if err := db.SaveMatch(e.league.ID, match); err != nil {
+		fmt.Printf("Failed to save match: %v\n", err)
+		return
+	}
+	if err := db.SaveMatchEvents(e.league.ID, match); err != nil {
+		fmt.Printf("Failed to save match events: %v\n", err)
+	}
+}
+
+// save every player's season totals, caller must hold the lock
+func (e *SeasonEngine) savePlayerStats() {
+	if db == nil || e.league.ID == 0 {
+		return
+	}
+	for _, p := range e.league.allPlayers() {
+		if err := db.SavePlayerStats(e.league.ID, p); err != nil {
+			fmt.Printf("Failed to save stats for %s: %v\n", p.Name, err)
+			return
+		}
+	}
+}
+
+// RecalculateStats rebuilds every team's stats from the played matches
+func (l *League) RecalculateStats() {
+	// reset all team stats first
+	for _, team := range l.Teams {
+		team.ResetTeamStats()
+		team.Availability = 1.0 // strength week by week comes from form, absentees are applied at the end
+	}
+
+	// go through all matches week by week, noting where everyone stood after each one.
+	// points adjustments come in at the end of the week they're for
+	// rearranged matches count in the week they're played, and a walkover
+	// awarded ahead of its week counts even though the week hasn't come
+	l.applyAdjustments(0)
+	l.PositionHistory = nil
+	for week := 1; week <= len(l.Fixtures); week++ {
+		for _, match := range l.weekMatches(week) {
+			// pinned results don't count until they're played
+			if match.IsPlayed {
+				match.HomeTeam.UpdateTeamStats(match.HomeGoals, match.AwayGoals, match.HomePenalties, match.AwayPenalties)
+				match.AwayTeam.UpdateTeamStats(match.AwayGoals, match.HomeGoals, match.AwayPenalties, match.HomePenalties)
+			}
+		}
+		if week <= l.weeksPlayed() {
+			l.applyAdjustments(week)
+			l.PositionHistory = append(l.PositionHistory, l.standingsAfter(week))
+		}
+	}
+	l.applyLateAdjustments()
+
+	// player totals come from the same matches
+	l.recalculatePlayerStats()
+	l.sortStandings()
+}
+
+// sort teams by points and the tie breakers
+func (l *League) sortStandings() {
+	sortTeams(l.Teams)
+}
+
+// sort any list of teams into table order
+func sortTeams(teams []*Team) {
+	sort.SliceStable(teams, func(i, j int) bool {
+		return compareTeams(teams[i], teams[j]) < 0
+	})
+}
+
+// compareTeams is below 0 if a goes above b in the table, above 0 if it goes below
+// and 0 if nothing separates them: points first, then the league's tie breakers
+func compareTeams(a, b *Team) int {
+	if a.Points != b.Points {
+		return b.Points - a.Points
+	}
+	for _, tieBreaker := range settings.TieBreakers {
+		var diff int
+		switch tieBreaker {
+		case TieBreakGoalDifference:
+			diff = b.GoalDifference - a.GoalDifference
+		case TieBreakGoalsFor:
+			diff = b.GoalsFor - a.GoalsFor
+		case TieBreakGoalsAgainst:
+			diff = a.GoalsAgainst - b.GoalsAgainst
+		case TieBreakWins:
+			diff = b.Won - a.Won
+		}
+		if diff != 0 {
+			return diff
+		}
+	}
+	return 0
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// HeadToHeadMatch is a played match between two teams, from this season or one before
+type HeadToHeadMatch struct {
+	LeagueID  int64
+	Season    string
+	Week      int
+	HomeTeam  string
+	AwayTeam  string
+	HomeGoals int
+	AwayGoals int
+}
+
+// record between two teams, from the first team's side
+type headToHeadRecord struct {
+	Played int
+	WinsA  int
+	Draws  int
+	WinsB  int
+	GoalsA int
+	GoalsB int
+}
+
+// add a match to the record
+func (r *headToHeadRecord) add(teamA string, m HeadToHeadMatch) {
+	goalsA, goalsB := m.HomeGoals, m.AwayGoals
+	if m.AwayTeam == teamA {
+		goalsA, goalsB = m.AwayGoals, m.HomeGoals
+	}
+	r.Played++
+	r.GoalsA += goalsA
+	r.GoalsB += goalsB
+	switch {
+	case goalsA > goalsB:
+		r.WinsA++
+	case goalsA == goalsB:
+		r.Draws++
+	default:
+		r.WinsB++
+	}
+}
+
+// HeadToHead compares two teams
+type HeadToHead struct {
+	TeamA      *Team
+	TeamB      *Team
+	ThisSeason headToHeadRecord
+	AllTime    headToHeadRecord
+	Matches    []HeadToHeadMatch // every meeting, oldest first
+	Next       *Match            // their next meeting this season, nil if there isn't one
+}
+
+// HeadToHead puts this season's meetings together with the ones from stored seasons
+func (l *League) HeadToHead(a, b *Team, stored []HeadToHeadMatch) HeadToHead {
+	h := HeadToHead{TeamA: a, TeamB: b}
+	for _, m := range stored {
+		h.AllTime.add(a.Name, m)
+		h.Matches = append(h.Matches, m)
+	}
+
+	for _, match := range l.teamMatches(a.Name) {
+		if match.HomeTeam != b && match.AwayTeam != b {
+			continue
+		}
+		if !match.IsPlayed {
+			if h.Next == nil {
+				h.Next = match
+			}
+			continue
+		}
+		m := HeadToHeadMatch{
+			LeagueID:  l.ID,
+			Season:    "this season",
+			Week:      match.Week,
+			HomeTeam:  match.HomeTeam.Name,
+			AwayTeam:  match.AwayTeam.Name,
+			HomeGoals: match.HomeGoals,
+			AwayGoals: match.AwayGoals,
+		}
+		h.ThisSeason.add(a.Name, m)
+		h.AllTime.add(a.Name, m)
+		h.Matches = append(h.Matches, m)
+	}
+	return h
+}
+
+// the last n results of a team's season, most recent last
+func lastResults(form []string, n int) string {
+	if len(form) > n {
+		form = form[len(form)-n:]
+	}
+	if len(form) == 0 {
+		return "-"
+	}
+	return strings.Join(form, " ")
+}
+
+// generateHeadToHeadReport writes the comparison out
+func generateHeadToHeadReport(league *League, h HeadToHead) string {
+	a, b := h.TeamA, h.TeamB
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s v %s\n", a.Name, b.Name))
+	sb.WriteString("------------------------------------------------------------\n")
+
+	sb.WriteString(fmt.Sprintf("%-14s %8s %6s %8s %10s\n", "", getShortName(a.Name)+" wins", "Draws", getShortName(b.Name)+" wins", "Goals"))
+	for _, row := range []struct {
+		label  string
+		record headToHeadRecord
+	}{{"This season", h.ThisSeason}, {"All seasons", h.AllTime}} {
+		r := row.record
+		sb.WriteString(fmt.Sprintf("%-14s %8d %6d %8d %6d - %d\n", row.label, r.WinsA, r.Draws, r.WinsB, r.GoalsA, r.GoalsB))
+	}
+
+	// how they compare right now
+	positions := make(map[*Team]int)
+	for i, t := range league.Teams {
+		positions[t] = i + 1
+	}
+	sb.WriteString(fmt.Sprintf("\n%-14s %-20s %-20s\n", "", a.Name, b.Name))
+	sb.WriteString(fmt.Sprintf("%-14s %-20s %-20s\n", "Position", positionName(positions[a]), positionName(positions[b])))
+	sb.WriteString(fmt.Sprintf("%-14s %-20d %-20d\n", "Points", a.Points, b.Points))
+	sb.WriteString(fmt.Sprintf("%-14s %-20s %-20s\n", "Goals", fmt.Sprintf("%d - %d", a.GoalsFor, a.GoalsAgainst), fmt.Sprintf("%d - %d", b.GoalsFor, b.GoalsAgainst)))
+	sb.WriteString(fmt.Sprintf("%-14s %-20s %-20s\n", "Form (last 5)", lastResults(league.formHistory(a.Name), 5), lastResults(league.formHistory(b.Name), 5)))
+	sb.WriteString(fmt.Sprintf("%-14s %-20s %-20s\n", "Strength",
+		fmt.Sprintf("%d (base %d)", a.CurrentStrength, a.BaseStrength), fmt.Sprintf("%d (base %d)", b.CurrentStrength, b.BaseStrength)))
+
+	// the next time they meet
+	sb.WriteString("\nNext meeting: ")
+	switch {
+	case h.Next == nil:
+		sb.WriteString("they don't meet again this season\n")
+	case h.Next.isPinned():
+		sb.WriteString(fmt.Sprintf("week %d, %s %d - %d %s (pinned)\n", h.Next.dueWeek(),
+			h.Next.HomeTeam.Name, h.Next.HomeGoals, h.Next.AwayGoals, h.Next.AwayTeam.Name))
+	default:
+		homeWin, draw, awayWin := matchOutcomeProbabilities(h.Next.HomeTeam, h.Next.AwayTeam)
+		sb.WriteString(fmt.Sprintf("week %d, %s v %s\n", h.Next.dueWeek(), h.Next.HomeTeam.Name, h.Next.AwayTeam.Name))
+		sb.WriteString(fmt.Sprintf("  %s win %.0f%%, draw %.0f%%, %s win %.0f%%\n",
+			getShortName(h.Next.HomeTeam.Name), homeWin*100, draw*100, getShortName(h.Next.AwayTeam.Name), awayWin*100))
+	}
+
+	// every meeting, most recent first
+	if len(h.Matches) > 0 {
+		sb.WriteString("\nMeetings\n")
+		for i := len(h.Matches) - 1; i >= 0; i-- {
+			m := h.Matches[i]
+			sb.WriteString(fmt.Sprintf("%-12s week %-2d %s %d - %d %s\n", m.Season, m.Week, m.HomeTeam, m.HomeGoals, m.AwayGoals, m.AwayTeam))
+		}
+	}
+	return sb.String()
+}
+
+// headToHeadReport looks up the meetings from stored seasons and writes the comparison.
+// both teams have to be in the current league
+func headToHeadReport(league *League, teamA, teamB string) (string, error) {
+	a, b := findTeam(league.Teams, teamA), findTeam(league.Teams, teamB)
+	if a == nil || b == nil {
+		return "", fmt.Errorf("both teams have to be in the current season")
+	}
+	if a == b {
+		return "", fmt.Errorf("pick two different teams")
+	}
+
+	var stored []HeadToHeadMatch
+	if db != nil {
+		var err error
+		stored, err = db.GetHeadToHead(a.Name, b.Name, league.ID)
+		if err != nil {
+			return "", fmt.Errorf("failed to load earlier seasons: %v", err)
+		}
+	}
+	return generateHeadToHeadReport(league, league.HeadToHead(a, b, stored)), nil
+}
+
+// showHeadToHead opens the dialog for comparing two teams
+func (g *GUI) showHeadToHead() {
+	var teamNames []string
+	g.engine.Read(func(league *League) {
+		for _, team := range league.Teams {
+			teamNames = append(teamNames, team.Name)
+		}
+	})
+	sort.Strings(teamNames)
+
+	report := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+	teamASelect := widget.NewSelect(teamNames, nil)
+	teamASelect.PlaceHolder = "Team..."
+	teamBSelect := widget.NewSelect(teamNames, nil)
+	teamBSelect.PlaceHolder = "Team..."
+
+	update := func(string) {
+		if teamASelect.Selected == "" || teamBSelect.Selected == "" {
+			return
+		}
+		var text string
+		var err error
+		g.engine.Read(func(league *League) {
+			text, err = headToHeadReport(league, teamASelect.Selected, teamBSelect.Selected)
+		})
+		if err != nil {
+			text = err.Error()
+		}
+		report.SetText(text)
+	}
+	teamASelect.OnChanged = update
+	teamBSelect.OnChanged = update
+
+	scroll := container.NewVScroll(report)
+	scroll.SetMinSize(fyne.NewSize(620, 450))
+	content := container.NewVBox(
+		widget.NewLabel("Head to Head"),
+		container.NewHBox(teamASelect, widget.NewLabel("v"), teamBSelect),
+		scroll,
+	)
+	dialog := widget.NewModalPopUp(content, g.window.Canvas())
+	dialog.Content = container.NewVBox(content, widget.NewButton("Close", func() {
+		dialog.Hide()
+	}))
+	dialog.Resize(fyne.NewSize(660, 580))
+	dialog.Show()
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// kinds of things that can be undone
+const (
+	ActionSimulateWeek = "simulate"
+	ActionEditResult   = "edit"
+	ActionClearFixed   = "clear"
+	ActionRearrange    = "rearrange" // postponed, abandoned or awarded
+)
+
+// HistoryEntry is one undoable change to the league. it keeps the matches it
+// touched as they were before and after, so undo and redo just put them back
+type HistoryEntry struct {
+	Seq         int // order the changes were made in
+	Action      string
+	Description string
+	WeekBefore  int
+	WeekAfter   int
+	Before      []MatchSnapshot
+	After       []MatchSnapshot
+	Undone      bool // sitting on the redo stack
+}
+
+// a match as it stood at some point, with teams and players by name so it can be saved
+type MatchSnapshot struct {
+	Week          int             `json:"week"`
+	HomeTeam      string          `json:"home_team"`
+	AwayTeam      string          `json:"away_team"`
+	HomeGoals     int             `json:"home_goals"`
+	AwayGoals     int             `json:"away_goals"`
+	HomePenalties int             `json:"home_penalties,omitempty"`
+	AwayPenalties int             `json:"away_penalties,omitempty"`
+	IsPlayed      bool            `json:"is_played"`
+	IsFixed       bool            `json:"is_fixed"`
+	Status        string          `json:"status,omitempty"`
+	PlayWeek      int             `json:"play_week,omitempty"`
+	Midweek       bool            `json:"midweek,omitempty"`
+	Kickoff       string          `json:"kickoff,omitempty"`
+	Events        []EventSnapshot `json:"events,omitempty"`
+}
+
+// a match event with the team and players by name
+type EventSnapshot struct {
+	Minute int    `json:"minute"`
+	Type   string `json:"type"`
+	Team   string `json:"team"`
+	Player string `json:"player,omitempty"`
+	Other  string `json:"other,omitempty"`
+	Weeks  int    `json:"weeks,omitempty"`
+}
+
+// take a snapshot of a match
+func (m *Match) snapshot() MatchSnapshot {
+	s := MatchSnapshot{
+		Week:          m.Week,
+		HomeTeam:      m.HomeTeam.Name,
+		AwayTeam:      m.AwayTeam.Name,
+		HomeGoals:     m.HomeGoals,
+		AwayGoals:     m.AwayGoals,
+		HomePenalties: m.HomePenalties,
+		AwayPenalties: m.AwayPenalties,
+		IsPlayed:      m.IsPlayed,
+		IsFixed:       m.IsFixed,
+		Status:        m.Status,
+		PlayWeek:      m.PlayWeek,
+		Midweek:       m.Midweek,
+	}
+	if !m.Kickoff.IsZero() {
+		s.Kickoff = m.Kickoff.Format(kickoffFormat)
+	}
+	for _, event := range m.Events {
+		e := EventSnapshot{Minute: event.Minute, Type: event.Type, Team: event.Team.Name, Weeks: event.Weeks}
+		if event.Player != nil {
+			e.Player = event.Player.Name
+		}
+		if event.Other != nil {
+			e.Other = event.Other.Name
+		}
+		s.Events = append(s.Events, e)
+	}
+	return s
+}
+
+// snapshot every match played in a week, rearranged ones included
+func (l *League) snapshotWeek(week int) []MatchSnapshot {
+	var snapshots []MatchSnapshot
+	for _, match := range l.weekMatches(week) {
+		snapshots = append(snapshots, match.snapshot())
+	}
+	return snapshots
+}
+
+// put a match back the way the snapshot has it, returns the match it changed
+func (l *League) restoreMatch(s MatchSnapshot) (*Match, error) {
+	match := l.findMatch(s.Week, s.HomeTeam, s.AwayTeam)
+	if match == nil {
+		return nil, fmt.Errorf("no match between %s and %s in week %d", s.HomeTeam, s.AwayTeam, s.Week)
+	}
+
+	match.HomeGoals = s.HomeGoals
+	match.AwayGoals = s.AwayGoals
+	match.HomePenalties = s.HomePenalties
+	match.AwayPenalties = s.AwayPenalties
+	match.IsPlayed = s.IsPlayed
+	match.IsFixed = s.IsFixed
+	match.Status = s.Status
+	match.PlayWeek = s.PlayWeek
+	match.Midweek = s.Midweek
+	match.Kickoff = time.Time{}
+	if s.Kickoff != "" {
+		kickoff, err := time.Parse(kickoffFormat, s.Kickoff)
+		if err != nil {
+			return nil, fmt.Errorf("%s v %s: %v", s.HomeTeam, s.AwayTeam, err)
+		}
+		match.Kickoff = kickoff
+	}
+	match.Events = nil
+	for _, e := range s.Events {
+		team := match.HomeTeam
+		if e.Team == match.AwayTeam.Name {
+			team = match.AwayTeam
+		}
+		match.Events = append(match.Events, MatchEvent{
+			Minute: e.Minute,
+			Type:   e.Type,
+			Team:   team,
+			Player: team.findPlayer(e.Player),
+			Other:  team.findPlayer(e.Other),
+			Weeks:  e.Weeks,
+		})
+	}
+	match.collectIncidents()
+	return match, nil
+}
+
+// find a squad player by name
+func (t *Team) findPlayer(name string) *Player {
+	if name == "" {
+		return nil
+	}
+	for _, p := range t.Squad {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// undoButtons builds the undo and redo buttons, disabled when there's nothing to do.
+// the descriptions are fetched by refreshDisplay as the engine is locked while rendering
+func (g *GUI) undoButtons() fyne.CanvasObject {
+	undo, redo := g.undoText, g.redoText
+
+	undoButton := widget.NewButton("Undo", g.undo)
+	if undo == "" {
+		undoButton.Disable()
+	} else {
+		undoButton.SetText("Undo " + undo)
+	}
+	redoButton := widget.NewButton("Redo", g.redo)
+	if redo == "" {
+		redoButton.Disable()
+	} else {
+		redoButton.SetText("Redo " + redo)
+	}
+	return container.NewHBox(undoButton, redoButton)
+}
+
+// set up ctrl+z and ctrl+y (cmd on a mac)
+func (g *GUI) addUndoShortcuts() {
+	g.window.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: fyne.KeyModifierShortcutDefault},
+		func(fyne.Shortcut) { g.undo() })
+	g.window.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyY, Modifier: fyne.KeyModifierShortcutDefault},
+		func(fyne.Shortcut) { g.redo() })
+}
+
+// history can't change under play all or a live week
+func (g *GUI) historyLocked() bool {
+	running, _, _ := g.autoPlay.status()
+	return running || g.livePlaying.Load()
+}
+
+// undo the last week or result edit
+func (g *GUI) undo() {
+	if g.historyLocked() {
+		return
+	}
+	if err := g.engine.Undo(); err != nil {
+		fmt.Printf("Failed to undo: %v\n", err)
+	}
+}
+
+// redo the last thing that was undone
+func (g *GUI) redo() {
+	if g.historyLocked() {
+		return
+	}
+	if err := g.engine.Redo(); err != nil {
+		fmt.Printf("Failed to redo: %v\n", err)
+	}
+}
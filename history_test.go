@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// a season with no database behind it, the same one every time
+func testEngine(t *testing.T) *SeasonEngine {
+	t.Helper()
+	settings = builtinSettings()
+	config := builtinLeagueConfig()
+	config.Seed = 42
+	config.Season = "2025/26" // so the dates, and the breaks, don't move with the year
+	e := &SeasonEngine{}
+	if err := e.StartLeague(config); err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+// every team's record and every match, to compare before and after. teams go by
+// name, level ones can come out of the table in either order
+func engineState(e *SeasonEngine) string {
+	var sb strings.Builder
+	e.Read(func(l *League) {
+		sb.WriteString(fmt.Sprintf("week %d\n", l.Week))
+		teams := append([]*Team(nil), l.Teams...)
+		sort.Slice(teams, func(i, j int) bool { return teams[i].Name < teams[j].Name })
+		for _, team := range teams {
+			sb.WriteString(fmt.Sprintf("%s %d %d-%d-%d %d:%d %d %v\n", team.Name, team.Played, team.Won, team.Drawn, team.Lost,
+				team.GoalsFor, team.GoalsAgainst, team.Points, team.Form))
+		}
+		for _, week := range l.Fixtures {
+			for _, m := range week {
+				sb.WriteString(fmt.Sprintf("%d %s %d-%d %s played=%v fixed=%v %q %d %v %s\n", m.Week, m.HomeTeam.Name, m.HomeGoals, m.AwayGoals,
+					m.AwayTeam.Name, m.IsPlayed, m.IsFixed, m.Status, m.PlayWeek, m.Midweek, m.Kickoff.Format(kickoffFormat)))
+			}
+		}
+	})
+	return sb.String()
+}
+
+// the teams in the first match of a week
+func firstMatch(e *SeasonEngine, week int) (string, string) {
+	var home, away string
+	e.Read(func(l *League) {
+		home, away = l.Fixtures[week-1][0].HomeTeam.Name, l.Fixtures[week-1][0].AwayTeam.Name
+	})
+	return home, away
+}
+
+func TestUndoRedo(t *testing.T) {
+	tests := []struct {
+		name   string
+		weeks  int // simulated first
+		change func(e *SeasonEngine) error
+	}{
+		{"simulate the first week", 0, func(e *SeasonEngine) error {
+			if _, ok := e.SimulateWeek(); !ok {
+				return fmt.Errorf("nothing simulated")
+			}
+			return nil
+		}},
+		{"simulate a later week", 2, func(e *SeasonEngine) error {
+			if _, ok := e.SimulateWeek(); !ok {
+				return fmt.Errorf("nothing simulated")
+			}
+			return nil
+		}},
+		{"edit a played result", 2, func(e *SeasonEngine) error {
+			home, away := firstMatch(e, 1)
+			return e.EditResult(1, home, away, 5, 0)
+		}},
+		{"pin a result still to come", 1, func(e *SeasonEngine) error {
+			home, away := firstMatch(e, 2)
+			return e.EditResult(2, home, away, 3, 3)
+		}},
+		{"postpone a match", 1, func(e *SeasonEngine) error {
+			home, away := firstMatch(e, 3)
+			return e.PostponeMatch(3, home, away, 8, true)
+		}},
+		{"abandon a played match", 2, func(e *SeasonEngine) error {
+			home, away := firstMatch(e, 1)
+			return e.AbandonMatch(1, home, away, 8, true)
+		}},
+		{"award a walkover", 1, func(e *SeasonEngine) error {
+			home, away := firstMatch(e, 2)
+			return e.AwardWalkover(2, home, away, home)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := testEngine(t)
+			for i := 0; i < tt.weeks; i++ {
+				e.SimulateWeek()
+			}
+			before := engineState(e)
+			if err := tt.change(e); err != nil {
+				t.Fatal(err)
+			}
+			after := engineState(e)
+			if after == before {
+				t.Fatal("the change didn't change anything")
+			}
+
+			if err := e.Undo(); err != nil {
+				t.Fatal(err)
+			}
+			if got := engineState(e); got != before {
+				t.Errorf("undo didn't put the season back:\n%s\nwant:\n%s", got, before)
+			}
+			if err := e.Redo(); err != nil {
+				t.Fatal(err)
+			}
+			if got := engineState(e); got != after {
+				t.Errorf("redo didn't put the change back:\n%s\nwant:\n%s", got, after)
+			}
+			if undo, redo := e.HistoryDescriptions(); undo == "" || redo != "" {
+				t.Errorf("after redo undo is %q and redo %q, want something to undo and nothing to redo", undo, redo)
+			}
+		})
+	}
+}
+
+func TestUndoAll(t *testing.T) {
+	e := testEngine(t)
+	start := engineState(e)
+	for i := 0; i < 2; i++ {
+		e.SimulateWeek()
+	}
+	home, away := firstMatch(e, 2)
+	if err := e.EditResult(2, home, away, 0, 4); err != nil {
+		t.Fatal(err)
+	}
+	end := engineState(e)
+
+	for i := 0; i < 3; i++ {
+		if err := e.Undo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := engineState(e); got != start {
+		t.Errorf("undoing everything didn't get back to the start:\n%s\nwant:\n%s", got, start)
+	}
+	if undo, _ := e.HistoryDescriptions(); undo != "" {
+		t.Errorf("still %q to undo", undo)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := e.Redo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := engineState(e); got != end {
+		t.Errorf("redoing everything didn't get back to the end:\n%s\nwant:\n%s", got, end)
+	}
+}
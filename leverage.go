@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+)
+
+// simulations per outcome when working out how much a fixture matters
+const leverageSimulations = 1000
+
+// the results we try for each fixture
+var leverageOutcomes = []struct {
+	label     string
+	homeGoals int
+	awayGoals int
+}{
+	{"Home win", 1, 0},
+	{"Draw", 1, 1},
+	{"Away win", 0, 1},
+}
+
+// FixtureLeverage is how much a fixture's result moves the final table
+type FixtureLeverage struct {
+	Week         int
+	HomeTeam     string
+	AwayTeam     string
+	Outcomes     []map[string][]float64 // position probabilities for each of leverageOutcomes
+	Swing        float64                // biggest difference any result makes to any team's chance of any position
+	MostAffected string                 // the team that swing belongs to
+}
+
+// FixtureLeverage plays the rest of the season out with the fixture pinned to each result in turn
+func (l *League) FixtureLeverage(match *Match, simulations int) FixtureLeverage {
+	leverage := FixtureLeverage{
+		Week:     match.Week,
+		HomeTeam: match.HomeTeam.Name,
+		AwayTeam: match.AwayTeam.Name,
+	}
+
+	for _, outcome := range leverageOutcomes {
+		scenario := &Scenario{
+			Name: outcome.label,
+			Pins: []ScenarioPin{{
+				Week:      match.Week,
+				HomeTeam:  match.HomeTeam.Name,
+				AwayTeam:  match.AwayTeam.Name,
+				HomeGoals: outcome.homeGoals,
+				AwayGoals: outcome.awayGoals,
+			}},
+		}
+		leverage.Outcomes = append(leverage.Outcomes, l.PositionProbabilities(simulations, scenario))
+	}
+
+	// compare the results position by position
+	for _, team := range l.Teams {
+		for position := range l.Teams {
+			lowest, highest := 100.0, 0.0
+			for _, probs := range leverage.Outcomes {
+				p := probs[team.Name][position]
+				if p < lowest {
+					lowest = p
+				}
+				if p > highest {
+					highest = p
+				}
+			}
+			if highest-lowest > leverage.Swing {
+				leverage.Swing = highest - lowest
+				leverage.MostAffected = team.Name
+			}
+		}
+	}
+	return leverage
+}
+
+// RankFixtures works out the leverage of every fixture still to be decided up to lastWeek,
+// the ones that matter most first
+func (l *League) RankFixtures(lastWeek, simulations int) []FixtureLeverage {
+	var ranked []FixtureLeverage
+	for week := l.upcomingWeek(); week <= lastWeek && week <= len(l.Fixtures); week++ {
+		for _, match := range l.weekMatches(week) {
+			if match.IsPlayed || match.isPinned() {
+				continue // already decided
+			}
+			ranked = append(ranked, l.FixtureLeverage(match, simulations))
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Swing > ranked[j].Swing
+	})
+	return ranked
+}
+
+// generateLeverageReport writes the ranked fixtures out with each result's title chances
+func generateLeverageReport(league *League, ranked []FixtureLeverage) string {
+	var sb strings.Builder
+	sb.WriteString("Most Important Fixtures\n")
+	sb.WriteString("--------------------------------------------------------------\n")
+	if len(ranked) == 0 {
+		sb.WriteString("No fixtures left to decide\n")
+		return sb.String()
+	}
+
+	for i, leverage := range ranked {
+		sb.WriteString(fmt.Sprintf("%2d. Week %-2d %s vs %s - swing %.1f%% (%s)\n",
+			i+1, leverage.Week, leverage.HomeTeam, leverage.AwayTeam, leverage.Swing, leverage.MostAffected))
+
+		// title chances under each result
+		for o, outcome := range leverageOutcomes {
+			sb.WriteString(fmt.Sprintf("      %-9s", outcome.label))
+			for _, team := range league.Teams {
+				sb.WriteString(fmt.Sprintf("  %s %5.1f%%", getShortName(team.Name), leverage.Outcomes[o][team.Name][0]))
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// start working out the upcoming fixtures' leverage if the league has changed since last time
+func (g *GUI) checkLeverage(league *League) {
+	key := fmt.Sprintf("%d:%d", league.ID, league.Revision)
+	if g.leverageKey == key {
+		return
+	}
+	g.leverage = nil
+
+	// no point while play all is racing through the weeks
+	if running, _, _ := g.autoPlay.status(); running {
+		g.leverageKey = ""
+		return
+	}
+	g.leverageKey = key
+	go g.updateLeverage(key)
+}
+
+// work out the leverage of the upcoming week's fixtures in the background and redraw when it's ready.
+// key says which state of the league it was worked out for
+func (g *GUI) updateLeverage(key string) {
+	var leverage map[string]FixtureLeverage
+	g.engine.Snapshot(func(league *League) {
+		week := league.upcomingWeek()
+		leverage = make(map[string]FixtureLeverage)
+		for _, l := range league.RankFixtures(week, leverageSimulations) {
+			leverage[fixtureKey(l.Week, l.HomeTeam, l.AwayTeam)] = l
+		}
+	})
+
+	fyne.Do(func() {
+		// the league could have moved on while we were busy
+		if g.leverageKey != key {
+			return
+		}
+		g.leverage = leverage
+		if !g.livePlaying.Load() {
+			g.refreshDisplay()
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// how long one match minute takes in live mode
+const liveMinuteDelay = 80 * time.Millisecond
+
+// how many lines the latest scores panel keeps
+const latestScoresLines = 10
+
+// a week being played out live
+type liveWeek struct {
+	matches     []Match // copies, so the engine is free to carry on
+	minute      int
+	skip        bool // jump straight to full time
+	clockLabel  *widget.Label
+	scoreLabels []*widget.Label
+	latestLabel *widget.Label
+	latest      []string
+}
+
+// playWeekLive plays the week's matches out with a ticking clock, then calls done on the ui thread.
+// the matches have already been played by the engine, this just reveals their timelines minute by minute
+func (g *GUI) playWeekLive(week int, done func()) {
+	live := &liveWeek{
+		clockLabel:  widget.NewLabelWithStyle("Kick off", fyne.TextAlignLeading, fyne.TextStyle{Bold: true, Monospace: true}),
+		latestLabel: widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Monospace: true}),
+	}
+	g.engine.Read(func(league *League) {
+		for _, match := range league.weekMatches(week) {
+			live.matches = append(live.matches, *match)
+		}
+	})
+	if len(live.matches) == 0 {
+		fyne.Do(done)
+		return
+	}
+	for i := range live.matches {
+		live.scoreLabels = append(live.scoreLabels,
+			widget.NewLabelWithStyle(live.scoreLine(&live.matches[i]), fyne.TextAlignLeading, fyne.TextStyle{Monospace: true}))
+	}
+
+	fyne.Do(func() {
+		scores := container.NewVBox(widget.NewLabel("Scores"))
+		for _, label := range live.scoreLabels {
+			scores.Add(label)
+		}
+		latest := container.NewVBox(widget.NewLabel("Latest Scores"), live.latestLabel)
+
+		skipButton := widget.NewButton("Skip to Full Time", func() {
+			live.skip = true
+		})
+		controls := container.NewHBox(skipButton)
+		if running, _, _ := g.autoPlay.status(); running {
+			// stopping play all doesn't need the rest of this week either
+			controls.Add(widget.NewLabel("  "))
+			controls.Add(g.autoPlayControls(func() {
+				live.skip = true
+			}))
+		}
+
+		g.window.SetContent(container.NewVBox(
+			widget.NewLabelWithStyle(fmt.Sprintf("Week %d - Live", week), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			live.clockLabel,
+			widget.NewLabel(""),
+			container.NewHBox(scores, widget.NewLabel("     "), latest),
+			widget.NewLabel(""),
+			controls,
+		))
+	})
+
+	time.AfterFunc(liveMinuteDelay, func() {
+		g.tickLive(live, done)
+	})
+}
+
+// move the live clock on a minute and schedule the next one
+func (g *GUI) tickLive(live *liveWeek, done func()) {
+	fyne.Do(func() {
+		// skipping plays everything that's left in one go
+		last := live.minute + 1
+		if live.skip {
+			last = matchMinutes
+		}
+		for live.minute < last {
+			live.minute++
+			live.reveal(live.minute)
+		}
+		live.refresh()
+
+		if live.minute >= matchMinutes {
+			live.clockLabel.SetText("Full time")
+			time.AfterFunc(500*time.Millisecond, func() {
+				fyne.Do(done)
+			})
+			return
+		}
+
+		time.AfterFunc(liveMinuteDelay, func() {
+			g.tickLive(live, done)
+		})
+	})
+}
+
+// add anything that happened in the given minute to the latest scores
+func (live *liveWeek) reveal(minute int) {
+	for i := range live.matches {
+		match := &live.matches[i]
+		for _, event := range match.Events {
+			if event.Minute != minute {
+				continue
+			}
+
+			var line string
+			switch event.Type {
+			case EventGoal:
+				home, away := match.scoreAt(minute)
+				line = fmt.Sprintf("%2d' GOAL %s %d-%d %s (%s)", minute,
+					getShortName(match.HomeTeam.Name), home, away, getShortName(match.AwayTeam.Name), event.Player.Name)
+			case EventRedCard:
+				line = fmt.Sprintf("%2d' RED  %s (%s)", minute, event.Player.Name, getShortName(event.Team.Name))
+			default:
+				continue // only goals and sendings off make the panel
+			}
+			live.latest = append([]string{line}, live.latest...)
+		}
+	}
+	if len(live.latest) > latestScoresLines {
+		live.latest = live.latest[:latestScoresLines]
+	}
+}
+
+// update the clock, scores and latest scores panel
+func (live *liveWeek) refresh() {
+	if live.minute == halfTimeMinute {
+		live.clockLabel.SetText("Half time")
+	} else {
+		live.clockLabel.SetText(fmt.Sprintf("%d'", live.minute))
+	}
+
+	for i := range live.matches {
+		live.scoreLabels[i].SetText(live.scoreLine(&live.matches[i]))
+	}
+
+	text := ""
+	for _, line := range live.latest {
+		text += line + "\n"
+	}
+	live.latestLabel.SetText(text)
+}
+
+// a match's score as it stands on the live clock
+func (live *liveWeek) scoreLine(match *Match) string {
+	home, away := match.scoreAt(live.minute)
+	return fmt.Sprintf("%-20s %d - %d  %-20s", match.HomeTeam.Name, home, away, match.AwayTeam.Name)
+}
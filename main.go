@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 )
@@ -8,18 +9,70 @@ import (
 var db *Database
 
 func main() {
+	// command line options, without any of them we start the gui
+	leverageFlag := flag.Bool("leverage", false, "print the remaining fixtures ranked by how much they matter, then exit")
+	weeksFlag := flag.Int("weeks", 0, "with -leverage, only look this many weeks ahead (0 for the rest of the season)")
+	simsFlag := flag.Int("sims", leverageSimulations, "with -leverage, simulations per result")
+	needsFlag := flag.String("needs", "", "print what the named team needs from its remaining fixtures, then exit")
+	positionFlag := flag.Int("position", 1, "with -needs, the position to finish in or above")
+	h2hFlag := flag.String("h2h", "", "print the head to head between this team and the -vs team, then exit")
+	vsFlag := flag.String("vs", "", "with -h2h, the other team")
+	chartFlag := flag.String("chart", "", "save the season's trend chart to this .svg or .png file, then exit")
+	plotFlag := flag.String("plot", TrendTitle, "with -chart, what to plot: title, position (expected) or bump (league position each week)")
+	newFlag := flag.Bool("new", false, "start a new league from the config instead of carrying on with the last one")
+	teamsFlag := flag.String("teams", "", "start a new league with exactly these teams, comma separated")
+	strengthFlag := flag.String("strength", "", "start a new league with these strengths, e.g. Arsenal=90,Chelsea=70")
+	seedFlag := flag.Int64("seed", 0, "start a new league with this seed, the same seed plays the same season")
+	configFlag := flag.String("config", defaultLeagueConfigFile, "league config to set new leagues up from, .toml or .yaml")
+	flag.Parse()
+
 	fmt.Printf("Premier League Simulator\n")
 	fmt.Printf("========================\n\n")
 
+	config, err := loadLeagueConfig(*configFlag)
+	if err != nil {
+		log.Fatalf("Failed to load league config: %v", err)
+	}
+	leagueConfig = config
+	leagueConfigPath = *configFlag
+
 	// Initialize database
 	fmt.Println("Initializing database...")
-	var err error
 	db, err = InitDatabase("premier_league.db")
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 	defer db.Close()
 
+	// any of the new league options starts one, then carries on with whatever else was asked for
+	seedSet := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			seedSet = true
+		}
+	})
+	if *newFlag || *teamsFlag != "" || *strengthFlag != "" || seedSet {
+		startNewLeague(*teamsFlag, *strengthFlag, *seedFlag, seedSet)
+	}
+
+	if *leverageFlag {
+		printLeverage(*weeksFlag, *simsFlag)
+		return
+	}
+	if *needsFlag != "" {
+		printRequirements(*needsFlag, *positionFlag)
+		return
+	}
+
+	if *h2hFlag != "" {
+		printHeadToHead(*h2hFlag, *vsFlag)
+		return
+	}
+	if *chartFlag != "" {
+		exportTrendChart(*chartFlag, *plotFlag)
+		return
+	}
+
 	fmt.Println("Starting GUI mode...")
 	gui := NewGUI()
 	gui.window.ShowAndRun()
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// kinds of things that happen in a match
+const (
+	EventGoal         = "goal"
+	EventYellowCard   = "yellow"
+	EventRedCard      = "red"
+	EventSubstitution = "sub"
+	EventInjury       = "injury"
+)
+
+// match engine settings
+const (
+	matchMinutes     = 90
+	halfTimeMinute   = 45
+	maxSubstitutions = 3
+	assistChance     = 0.75 // most goals have an assist
+	penaltyChance    = 0.75 // a shootout kick that goes in
+	shootoutKicks    = 5    // each before it goes to sudden death
+)
+
+// something that happened in a match
+type MatchEvent struct {
+	Minute int
+	Type   string
+	Team   *Team
+	Player *Player // scorer, booked player, injured player or player going off
+	Other  *Player // who set the goal up, or who came on
+	Weeks  int     // how long an injury keeps the player out
+}
+
+// one team's side of a match while it's being played
+type matchSide struct {
+	team     *Team
+	onPitch  []*Player
+	bench    []*Player
+	subsLeft int
+	booked   map[*Player]bool
+}
+
+// something planned to happen at a minute, the player involved is picked when it happens
+// so they're always someone still on the pitch
+type plannedEvent struct {
+	side      *matchSide
+	eventType string
+}
+
+// pick the line up for the week from the players available
+func newMatchSide(team *Team, week int) *matchSide {
+	available := team.availableSquad(week)
+	eleven, outOfPosition := startingEleven(available)
+	eleven = append(eleven, outOfPosition...)
+
+	starting := make(map[*Player]bool)
+	for _, p := range eleven {
+		starting[p] = true
+	}
+	var bench []*Player
+	for _, p := range available {
+		if !starting[p] {
+			bench = append(bench, p)
+		}
+	}
+
+	return &matchSide{
+		team:     team,
+		onPitch:  eleven,
+		bench:    bench,
+		subsLeft: maxSubstitutions,
+		booked:   make(map[*Player]bool),
+	}
+}
+
+// take a player off the pitch
+func (s *matchSide) remove(player *Player) {
+	for i, p := range s.onPitch {
+		if p == player {
+			s.onPitch = append(s.onPitch[:i], s.onPitch[i+1:]...)
+			return
+		}
+	}
+}
+
+// random outfield player currently on the pitch
+func (s *matchSide) randomOutfielder() *Player {
+	var outfield []*Player
+	for _, p := range s.onPitch {
+		if p.Position != Goalkeeper {
+			outfield = append(outfield, p)
+		}
+	}
+	if len(outfield) == 0 {
+		return nil
+	}
+	return outfield[rng.Intn(len(outfield))]
+}
+
+// bring someone off the bench for a player, like for like if we can
+func (s *matchSide) substitute(off *Player) *Player {
+	if s.subsLeft == 0 || len(s.bench) == 0 {
+		return nil
+	}
+
+	pick := 0
+	for i, p := range s.bench {
+		if p.Position == off.Position {
+			pick = i
+			break
+		}
+	}
+	on := s.bench[pick]
+	s.bench = append(s.bench[:pick], s.bench[pick+1:]...)
+	s.subsLeft--
+
+	s.remove(off)
+	s.onPitch = append(s.onPitch, on)
+	return on
+}
+
+// plan n events of a type at random minutes
+func planEvents(plan map[int][]plannedEvent, side *matchSide, eventType string, n, from, to int) {
+	for i := 0; i < n; i++ {
+		minute := from + rng.Intn(to-from+1)
+		plan[minute] = append(plan[minute], plannedEvent{side: side, eventType: eventType})
+	}
+}
+
+// playMatch simulates a match and records what happened in it
+func (m *Match) playMatch() {
+	homeGoals, awayGoals := predictMatchResult(m.HomeTeam, m.AwayTeam, rng)
+	m.playTimeline(homeGoals, awayGoals)
+	m.IsPlayed = true
+}
+
+// playTimeline plays the match out minute by minute around the given final score
+func (m *Match) playTimeline(homeGoals, awayGoals int) {
+	m.HomeGoals = homeGoals
+	m.AwayGoals = awayGoals
+	m.HomePenalties, m.AwayPenalties = 0, 0
+	if homeGoals == awayGoals && settings.Points.Shootouts {
+		m.HomePenalties, m.AwayPenalties = penaltyShootout(rng)
+	}
+	m.Events = nil
+
+	home := newMatchSide(m.HomeTeam, m.dueWeek())
+	away := newMatchSide(m.AwayTeam, m.dueWeek())
+
+	// decide when things happen
+	plan := make(map[int][]plannedEvent)
+	planEvents(plan, home, EventGoal, homeGoals, 1, matchMinutes)
+	planEvents(plan, away, EventGoal, awayGoals, 1, matchMinutes)
+	for _, side := range []*matchSide{home, away} {
+		bookings := 0
+		for range side.onPitch {
+			if rng.Float64() < yellowCardChance {
+				bookings++
+			}
+		}
+		planEvents(plan, side, EventYellowCard, bookings, 1, matchMinutes)
+		if rng.Float64() < redCardChance {
+			planEvents(plan, side, EventRedCard, 1, 1, matchMinutes)
+		}
+		if rng.Float64() < injuryChance {
+			planEvents(plan, side, EventInjury, 1, 1, matchMinutes)
+		}
+		planEvents(plan, side, EventSubstitution, maxSubstitutions, 55, 85)
+	}
+
+	for minute := 1; minute <= matchMinutes; minute++ {
+		for _, planned := range plan[minute] {
+			m.playEvent(minute, planned)
+		}
+	}
+
+	m.collectIncidents()
+}
+
+// penaltyShootout is the score of a shootout: five kicks each, then sudden death
+func penaltyShootout(r *rand.Rand) (int, int) {
+	var home, away int
+	for kick := 1; ; kick++ {
+		if r.Float64() < penaltyChance {
+			home++
+		}
+		if r.Float64() < penaltyChance {
+			away++
+		}
+		// over once one side can't be caught with the kicks left
+		left := max(shootoutKicks-kick, 0)
+		if home > away+left || away > home+left {
+			return home, away
+		}
+	}
+}
+
+// " (4 - 3 pens)" for a match settled on penalties, blank for any other
+func (m *Match) penaltiesNote() string {
+	if m.HomePenalties == m.AwayPenalties {
+		return ""
+	}
+	return fmt.Sprintf(" (%d - %d pens)", m.HomePenalties, m.AwayPenalties)
+}
+
+// play out a planned event at the given minute
+func (m *Match) playEvent(minute int, planned plannedEvent) {
+	side := planned.side
+	event := MatchEvent{Minute: minute, Type: planned.eventType, Team: side.team}
+
+	switch planned.eventType {
+	case EventGoal:
+		event.Player = pickPlayer(side.onPitch, scoringWeight, nil)
+		if event.Player == nil && len(side.onPitch) > 0 {
+			event.Player = side.onPitch[0] // the goal still counts
+		}
+		if rng.Float64() < assistChance {
+			event.Other = pickPlayer(side.onPitch, assistWeight, event.Player)
+		}
+
+	case EventYellowCard:
+		event.Player = side.randomOutfielder()
+		if event.Player == nil {
+			return
+		}
+		if side.booked[event.Player] {
+			// second yellow means a red
+			m.Events = append(m.Events, event)
+			event.Type = EventRedCard
+			side.remove(event.Player)
+		}
+		side.booked[event.Player] = true
+
+	case EventRedCard:
+		event.Player = side.randomOutfielder()
+		if event.Player == nil {
+			return
+		}
+		side.remove(event.Player)
+
+	case EventInjury:
+		if len(side.onPitch) == 0 {
+			return
+		}
+		event.Player = side.onPitch[rng.Intn(len(side.onPitch))]
+		event.Weeks = injuryLength()
+		m.Events = append(m.Events, event)
+		if on := side.substitute(event.Player); on != nil {
+			m.Events = append(m.Events, MatchEvent{
+				Minute: minute, Type: EventSubstitution, Team: side.team, Player: event.Player, Other: on,
+			})
+		} else {
+			side.remove(event.Player) // no subs left, down to ten
+		}
+		return
+
+	case EventSubstitution:
+		off := side.randomOutfielder()
+		if off == nil {
+			return
+		}
+		on := side.substitute(off)
+		if on == nil {
+			return
+		}
+		event.Player = off
+		event.Other = on
+	}
+
+	if event.Player == nil {
+		return
+	}
+	m.Events = append(m.Events, event)
+}
+
+// keep the goals, cards and injuries lists in step with the timeline
+func (m *Match) collectIncidents() {
+	m.Goals = nil
+	m.Cards = nil
+	m.Injuries = nil
+	for _, event := range m.Events {
+		switch event.Type {
+		case EventGoal:
+			m.Goals = append(m.Goals, Goal{Team: event.Team, Scorer: event.Player, Assister: event.Other})
+		case EventYellowCard:
+			m.Cards = append(m.Cards, Card{Team: event.Team, Player: event.Player})
+		case EventRedCard:
+			m.Cards = append(m.Cards, Card{Team: event.Team, Player: event.Player, Red: true})
+		case EventInjury:
+			m.Injuries = append(m.Injuries, Injury{Team: event.Team, Player: event.Player, Weeks: event.Weeks})
+		}
+	}
+}
+
+// how long an injury keeps a player out - most knocks are short, the odd one is serious
+func injuryLength() int {
+	weeks := rng.Intn(3) + 1
+	if rng.Float64() < 0.15 {
+		weeks += rng.Intn(6) + 3
+	}
+	return weeks
+}
+
+// score at half time, worked out from the goals in the timeline
+func (m *Match) halfTimeScore() (int, int) {
+	return m.scoreAt(halfTimeMinute)
+}
+
+// score at the end of the given minute
+func (m *Match) scoreAt(minute int) (int, int) {
+	if len(m.Events) == 0 {
+		return m.HomeGoals, m.AwayGoals // no timeline, all we have is the result
+	}
+
+	home, away := 0, 0
+	for _, event := range m.Events {
+		if event.Type != EventGoal || event.Minute > minute {
+			continue
+		}
+		if event.Team == m.HomeTeam {
+			home++
+		} else {
+			away++
+		}
+	}
+	return home, away
+}
+
+// generateMatchReport writes the match timeline out as text
+func (m *Match) generateMatchReport() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %d - %d %s%s\n", m.HomeTeam.Name, m.HomeGoals, m.AwayGoals, m.AwayTeam.Name, m.penaltiesNote()))
+	htHome, htAway := m.halfTimeScore()
+	sb.WriteString(fmt.Sprintf("Half time: %d - %d\n", htHome, htAway))
+	sb.WriteString("--------------------------------------------------\n")
+
+	if len(m.Events) == 0 {
+		sb.WriteString("No match report available\n")
+		return sb.String()
+	}
+
+	events := make([]MatchEvent, len(m.Events))
+	copy(events, m.Events)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Minute < events[j].Minute
+	})
+
+	halfTimeShown := false
+	for _, event := range events {
+		if !halfTimeShown && event.Minute > halfTimeMinute {
+			sb.WriteString(fmt.Sprintf("     --- Half time %d - %d ---\n", htHome, htAway))
+			halfTimeShown = true
+		}
+
+		var line string
+		switch event.Type {
+		case EventGoal:
+			line = "GOAL   " + event.Player.Name
+			if event.Other != nil {
+				line += fmt.Sprintf(" (assist %s)", event.Other.Name)
+			}
+		case EventYellowCard:
+			line = "YELLOW " + event.Player.Name
+		case EventRedCard:
+			line = "RED    " + event.Player.Name
+		case EventInjury:
+			line = "INJURY " + event.Player.Name
+		case EventSubstitution:
+			line = fmt.Sprintf("SUB    %s on, %s off", event.Other.Name, event.Player.Name)
+		}
+		sb.WriteString(fmt.Sprintf("%3d'  %-5s %s\n", event.Minute, getShortName(event.Team.Name), line))
+	}
+	if !halfTimeShown {
+		sb.WriteString(fmt.Sprintf("     --- Half time %d - %d ---\n", htHome, htAway))
+	}
+	sb.WriteString("     --- Full time ---\n")
+	return sb.String()
+}
+
+// showMatchReport opens the match report, with the option to edit the result
+func (g *GUI) showMatchReport(match Match) {
+	report := widget.NewLabelWithStyle(match.generateMatchReport(), fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+	scroll := container.NewVScroll(report)
+	scroll.SetMinSize(fyne.NewSize(560, 400))
+
+	content := container.NewVBox(
+		widget.NewLabel(strings.TrimSpace(fmt.Sprintf("Week %d Match Report  %s", match.dueWeek(), match.dateText()))),
+		scroll,
+	)
+
+	dialog := widget.NewModalPopUp(content, g.window.Canvas())
+
+	buttons := container.NewHBox(
+		widget.NewButton("Edit Result", func() {
+			dialog.Hide()
+			g.editMatchResult(match)
+		}),
+	)
+	if match.IsPlayed && match.Status != MatchWalkover {
+		// void the result and play it again later
+		buttons.Add(widget.NewButton("Abandon", func() {
+			dialog.Hide()
+			g.showRearrange(fixtureKey(match.Week, match.HomeTeam.Name, match.AwayTeam.Name))
+		}))
+	}
+	if match.IsFixed {
+		// hand the result back to the simulation
+		buttons.Add(widget.NewButton("Clear Override", func() {
+			dialog.Hide()
+			if err := g.engine.ClearOverride(match.Week, match.HomeTeam.Name, match.AwayTeam.Name); err != nil {
+				fmt.Printf("Failed to clear override: %v\n", err)
+			}
+		}))
+	}
+	buttons.Add(widget.NewButton("Close", func() {
+		dialog.Hide()
+	}))
+
+	dialog.Content = container.NewVBox(content, buttons)
+	dialog.Resize(fyne.NewSize(600, 500))
+	dialog.Show()
+}
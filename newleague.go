@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// find a team in the config by name, ignoring case
+func (c LeagueConfig) findTeam(name string) (TeamConfig, bool) {
+	for _, team := range c.Teams {
+		if strings.EqualFold(team.Name, strings.TrimSpace(name)) {
+			return team, true
+		}
+	}
+	return TeamConfig{}, false
+}
+
+// withTeams is the config with exactly the named teams playing
+func (c LeagueConfig) withTeams(names []string) (LeagueConfig, error) {
+	var teams []TeamConfig
+	for _, name := range names {
+		team, ok := c.findTeam(name)
+		if !ok {
+			return c, fmt.Errorf("%s isn't one of the teams in the config", name)
+		}
+		teams = append(teams, team)
+	}
+	c.Teams = teams
+	c.TeamCount = 0
+	return c, c.validate()
+}
+
+// withStrengths is the config with some of the teams' strengths changed
+func (c LeagueConfig) withStrengths(strengths map[string]int) (LeagueConfig, error) {
+	c.Teams = append([]TeamConfig(nil), c.Teams...)
+	for name, strength := range strengths {
+		found := false
+		for i := range c.Teams {
+			if strings.EqualFold(c.Teams[i].Name, name) {
+				c.Teams[i].Strength = strength
+				found = true
+			}
+		}
+		if !found {
+			return c, fmt.Errorf("%s isn't one of the teams in the config", name)
+		}
+	}
+	return c, c.validate()
+}
+
+// parse "Arsenal=90,Chelsea=70" into strengths by team
+func parseStrengths(s string) (map[string]int, error) {
+	strengths := make(map[string]int)
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q has to be team=strength", strings.TrimSpace(part))
+		}
+		strength, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%s: strength has to be a whole number", strings.TrimSpace(name))
+		}
+		strengths[strings.TrimSpace(name)] = strength
+	}
+	return strengths, nil
+}
+
+// the season after this one: 2025 becomes 2026 and 2025/26 becomes 2026/27. anything else
+// comes back blank, which a new league takes as the current year
+func nextSeasonName(season string) string {
+	if year, err := strconv.Atoi(season); err == nil {
+		return strconv.Itoa(year + 1)
+	}
+	first, second, ok := strings.Cut(season, "/")
+	start, err1 := strconv.Atoi(first)
+	end, err2 := strconv.Atoi(second)
+	if !ok || err1 != nil || err2 != nil {
+		return ""
+	}
+	// the second year keeps its digits, 2099/00 goes on to 2100/01
+	width := len(second)
+	end++
+	if width < 4 {
+		end %= 100
+	}
+	return fmt.Sprintf("%d/%0*d", start+1, width, end)
+}
+
+// a config for the next season of this league: the same teams at the same strengths,
+// the same rules, and a seed following on from this season's
+func (l *League) nextSeasonConfig() LeagueConfig {
+	config := leagueConfig
+	config.Name = l.Name
+	config.Season = nextSeasonName(l.Season)
+	config.TeamCount = 0
+	config.Teams = nil
+	for _, t := range l.Teams {
+		team, _ := leagueConfig.team(t.Name)
+		team.Name = t.Name
+		team.Strength = t.BaseStrength
+		config.Teams = append(config.Teams, team)
+	}
+	config.setSettings(settings)
+
+	// every team plays the others once a round, plus a week off each with an odd number
+	weeksPerRound := len(l.Teams) - 1
+	if len(l.Teams)%2 == 1 {
+		weeksPerRound++
+	}
+	config.Rounds = max(len(l.Fixtures)/weeksPerRound, 1)
+
+	config.Seed = 0
+	if l.Seed != 0 {
+		config.Seed = l.Seed + 1
+	}
+	return config
+}
+
+// showNewLeague opens the dialog for setting up a new league: which teams, how strong
+// they are and whether the season is seeded
+func (g *GUI) showNewLeague() {
+	config := g.engine.LeagueConfig()
+
+	// one row per team: ticked to play in chosen mode, with its strength
+	type teamRow struct {
+		check    *widget.Check
+		strength *widget.Entry
+	}
+	var rows []teamRow
+	teamGrid := container.NewGridWithColumns(3)
+	for _, team := range config.Teams {
+		row := teamRow{
+			check:    widget.NewCheck("", nil),
+			strength: widget.NewEntry(),
+		}
+		row.strength.SetText(strconv.Itoa(team.Strength))
+		rows = append(rows, row)
+		teamGrid.Add(row.check)
+		teamGrid.Add(widget.NewLabel(team.Name))
+		teamGrid.Add(row.strength)
+	}
+
+	teamCountEntry := widget.NewEntry()
+	teamCountEntry.SetText(strconv.Itoa(config.teamCount()))
+	roundsEntry := widget.NewEntry()
+	roundsEntry.SetText(strconv.Itoa(config.Rounds))
+	seedEntry := widget.NewEntry()
+	seedEntry.SetText(strconv.FormatInt(config.Seed, 10))
+
+	const randomTeams, chosenTeams = "Pick teams at random", "Choose the teams"
+	mode := widget.NewRadioGroup([]string{randomTeams, chosenTeams}, func(selected string) {
+		for _, row := range rows {
+			if selected == chosenTeams {
+				row.check.Enable()
+			} else {
+				row.check.Disable()
+			}
+		}
+		if selected == chosenTeams {
+			teamCountEntry.Disable()
+		} else {
+			teamCountEntry.Enable()
+		}
+	})
+	mode.Horizontal = true
+	mode.SetSelected(randomTeams)
+
+	// what's in the dialog as a config, checked
+	read := func() (LeagueConfig, error) {
+		c := config
+		c.Teams = append([]TeamConfig(nil), config.Teams...)
+		var chosen []TeamConfig
+		for i, row := range rows {
+			strength, err := strconv.Atoi(strings.TrimSpace(row.strength.Text))
+			if err != nil {
+				return c, fmt.Errorf("%s: strength has to be a whole number", c.Teams[i].Name)
+			}
+			c.Teams[i].Strength = strength
+			if row.check.Checked {
+				chosen = append(chosen, c.Teams[i])
+			}
+		}
+
+		var err error
+		if c.Rounds, err = strconv.Atoi(strings.TrimSpace(roundsEntry.Text)); err != nil {
+			return c, fmt.Errorf("rounds has to be a whole number")
+		}
+		if c.Seed, err = strconv.ParseInt(strings.TrimSpace(seedEntry.Text), 10, 64); err != nil {
+			return c, fmt.Errorf("seed has to be a whole number, 0 for random")
+		}
+		if mode.Selected == chosenTeams {
+			if len(chosen) < 2 {
+				return c, fmt.Errorf("tick at least 2 teams")
+			}
+			c.Teams = chosen
+			c.TeamCount = 0
+		} else if c.TeamCount, err = strconv.Atoi(strings.TrimSpace(teamCountEntry.Text)); err != nil {
+			return c, fmt.Errorf("teams has to be a whole number")
+		}
+		return c, c.validate()
+	}
+
+	optionsGrid := container.NewGridWithColumns(3,
+		widget.NewLabel("Teams:"), teamCountEntry, widget.NewLabel("picked at random from the list"),
+		widget.NewLabel("Rounds:"), roundsEntry, widget.NewLabel("times everyone plays everyone"),
+		widget.NewLabel("Seed:"), seedEntry, widget.NewLabel("the same seed plays the same season, 0 for random"),
+	)
+	statusLabel := widget.NewLabel("")
+
+	teamScroll := container.NewVScroll(teamGrid)
+	teamScroll.SetMinSize(fyne.NewSize(500, 260))
+	content := container.NewVBox(
+		widget.NewLabelWithStyle("New League", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabel("The current season is put away, it still counts for head to head. Strengths are 1 - 200."),
+		mode,
+		optionsGrid,
+		teamScroll,
+		statusLabel,
+	)
+	dialog := widget.NewModalPopUp(content, g.window.Canvas())
+
+	buttons := container.NewHBox(
+		widget.NewButton("Start", func() {
+			c, err := read()
+			if err != nil {
+				statusLabel.SetText(err.Error())
+				return
+			}
+			g.stopAutoPlay()
+			if err := g.engine.StartLeague(c); err != nil {
+				statusLabel.SetText(fmt.Sprintf("Failed to start: %v", err))
+				return
+			}
+			g.showAllResults = false
+			dialog.Hide()
+		}),
+		widget.NewButton("Cancel", func() {
+			dialog.Hide()
+		}),
+	)
+	dialog.Content = container.NewVBox(content, buttons)
+	dialog.Resize(fyne.NewSize(620, 560))
+	dialog.Show()
+}
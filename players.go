@@ -0,0 +1,258 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// player positions
+const (
+	Goalkeeper = "GK"
+	Defender   = "DEF"
+	Midfielder = "MID"
+	Forward    = "FWD"
+)
+
+// single player in a team's squad
+type Player struct {
+	Name     string
+	TeamName string
+	Position string // GK, DEF, MID or FWD
+	Rating   int
+	Goals    int // season totals, worked out from the match goals
+	Assists  int
+	Yellows  int
+	Reds     int
+
+	UnavailableUntil int    // last week the player misses, 0 if fit
+	Absence          string // "Injured" or "Suspended"
+}
+
+// a goal scored in a match
+type Goal struct {
+	Team     *Team
+	Scorer   *Player
+	Assister *Player // nil if nobody set it up
+}
+
+// how the squad is made up: 2 keepers, 6 defenders, 6 midfielders, 4 forwards
+var squadLayout = []struct {
+	position string
+	count    int
+}{
+	{Goalkeeper, 2},
+	{Defender, 6},
+	{Midfielder, 6},
+	{Forward, 4},
+}
+
+// name pools for the generated squads
+var (
+	playerFirstNames = []string{
+		"James", "Jack", "Harry", "Oliver", "Ben", "Luke", "Kai", "Marcus", "Declan", "Phil",
+		"Bukayo", "Mason", "Reece", "Conor", "Jarrod", "Ollie", "Tyrone", "Ezri", "Morgan", "Jordan",
+		"Kieran", "Aaron", "Levi", "Callum", "Dominic", "Trent", "Eddie", "Jacob", "Noni", "Cole",
+	}
+	playerLastNames = []string{
+		"Walker", "Stones", "Rice", "Foden", "Saka", "Kane", "Mount", "James", "Gallagher", "Bowen",
+		"Watkins", "Mings", "Pope", "Trippier", "Toney", "Maddison", "Bellingham", "Palmer", "Shaw", "Rashford",
+		"Konsa", "Guehi", "Gordon", "Eze", "Wharton", "Branthwaite", "Alexander", "Nketiah", "Madueke", "Ramsdale",
+	}
+)
+
+// build a squad for a team. the squad is seeded from the team name
+// so the same team always gets the same players
+func generateSquad(team *Team) []*Player {
+	hash := fnv.New64a()
+	hash.Write([]byte(team.Name))
+	r := rand.New(rand.NewSource(int64(hash.Sum64())))
+
+	used := make(map[string]bool)
+	var squad []*Player
+	for _, slot := range squadLayout {
+		for i := 0; i < slot.count; i++ {
+			// keep picking until we find a name nobody in the squad has
+			var name string
+			for name == "" || used[name] {
+				name = playerFirstNames[r.Intn(len(playerFirstNames))] + " " +
+					playerLastNames[r.Intn(len(playerLastNames))]
+			}
+			used[name] = true
+
+			// first choice players sit around the team strength, backups a bit below
+			rating := team.BaseStrength + r.Intn(9) - 4
+			if i >= slot.count/2 {
+				rating -= 4
+			}
+
+			squad = append(squad, &Player{
+				Name:     name,
+				TeamName: team.Name,
+				Position: slot.position,
+				Rating:   rating,
+			})
+		}
+	}
+	return squad
+}
+
+// how likely a player is to score, by position
+func scoringWeight(p *Player) int {
+	switch p.Position {
+	case Forward:
+		return 6 * p.Rating
+	case Midfielder:
+		return 3 * p.Rating
+	case Defender:
+		return 1 * p.Rating
+	}
+	return 0 // keepers don't score
+}
+
+// how likely a player is to set up a goal, by position
+func assistWeight(p *Player) int {
+	switch p.Position {
+	case Midfielder:
+		return 5 * p.Rating
+	case Forward:
+		return 3 * p.Rating
+	case Defender:
+		return 2 * p.Rating
+	}
+	return 0
+}
+
+// pick a player from the squad using the given weights
+func pickPlayer(squad []*Player, weight func(*Player) int, exclude *Player) *Player {
+	total := 0
+	for _, p := range squad {
+		if p != exclude {
+			total += weight(p)
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	roll := rng.Intn(total)
+	for _, p := range squad {
+		if p == exclude {
+			continue
+		}
+		roll -= weight(p)
+		if roll < 0 {
+			return p
+		}
+	}
+	return nil
+}
+
+// tally up goals, assists, cards and absences for every player from the played matches.
+// matches are replayed in order so bans and injuries land in the right weeks
+func (l *League) recalculatePlayerStats() {
+	for _, team := range l.Teams {
+		for _, p := range team.Squad {
+			p.Goals = 0
+			p.Assists = 0
+			p.Yellows = 0
+			p.Reds = 0
+			p.UnavailableUntil = 0
+			p.Absence = ""
+		}
+	}
+
+	for week := 1; week <= len(l.Fixtures); week++ {
+		for _, match := range l.weekMatches(week) {
+			if !match.IsPlayed {
+				continue
+			}
+			for _, goal := range match.Goals {
+				goal.Scorer.Goals++
+				if goal.Assister != nil {
+					goal.Assister.Assists++
+				}
+			}
+			match.applyIncidents()
+		}
+	}
+
+	// absentees weaken the team for the week coming up
+	for _, team := range l.Teams {
+		team.Availability = team.availabilityFactor(l.upcomingWeek())
+		team.updateTeamStrength()
+	}
+}
+
+// every player in the league
+func (l *League) allPlayers() []*Player {
+	var players []*Player
+	for _, team := range l.Teams {
+		players = append(players, team.Squad...)
+	}
+	return players
+}
+
+// TopScorers returns the golden boot race, best first
+func (l *League) TopScorers(n int) []*Player {
+	players := l.allPlayers()
+	sort.SliceStable(players, func(i, j int) bool {
+		if players[i].Goals != players[j].Goals {
+			return players[i].Goals > players[j].Goals
+		}
+		return players[i].Assists > players[j].Assists
+	})
+	return topPlayers(players, n, func(p *Player) int { return p.Goals })
+}
+
+// TopAssists returns the players with the most assists, best first
+func (l *League) TopAssists(n int) []*Player {
+	players := l.allPlayers()
+	sort.SliceStable(players, func(i, j int) bool {
+		if players[i].Assists != players[j].Assists {
+			return players[i].Assists > players[j].Assists
+		}
+		return players[i].Goals > players[j].Goals
+	})
+	return topPlayers(players, n, func(p *Player) int { return p.Assists })
+}
+
+// take the first n players that actually have something to show
+func topPlayers(sorted []*Player, n int, value func(*Player) int) []*Player {
+	var top []*Player
+	for _, p := range sorted {
+		if len(top) >= n || value(p) == 0 {
+			break
+		}
+		top = append(top, p)
+	}
+	return top
+}
+
+// generateScorersTable creates the golden boot and assists tables side by side
+func (g *GUI) generateScorersTable(league *League) string {
+	scorers := league.TopScorers(5)
+	assists := league.TopAssists(5)
+	if len(scorers) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%-32s     %-32s\n", "Golden Boot", "Most Assists"))
+	sb.WriteString(fmt.Sprintf("%-32s     %-32s\n", strings.Repeat("-", 32), strings.Repeat("-", 32)))
+	for i := 0; i < len(scorers) || i < len(assists); i++ {
+		left, right := "", ""
+		if i < len(scorers) {
+			p := scorers[i]
+			left = fmt.Sprintf("%-20.20s %-5s %3d", p.Name, getShortName(p.TeamName), p.Goals)
+		}
+		if i < len(assists) {
+			p := assists[i]
+			right = fmt.Sprintf("%-20.20s %-5s %3d", p.Name, getShortName(p.TeamName), p.Assists)
+		}
+		sb.WriteString(fmt.Sprintf("%-32s     %-32s\n", left, right))
+	}
+	return sb.String()
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// points awarded in the predictor league
+const (
+	exactScorePoints    = 3 // got the exact score right
+	correctResultPoints = 1 // got the winner (or the draw) right
+)
+
+// someone taking part in the predictor league
+type Predictor struct {
+	ID   int64
+	Name string
+}
+
+// a predicted score for one fixture
+type Prediction struct {
+	PredictorID int64
+	Week        int
+	HomeTeam    string
+	AwayTeam    string
+	HomeGoals   int
+	AwayGoals   int
+}
+
+// a predictor's row in the leaderboard
+type PredictorStanding struct {
+	Name           string
+	Scored         int // predictions for matches that have been played
+	ExactScores    int
+	CorrectResults int // right result but wrong score
+	Points         int
+}
+
+// work out how many points a prediction earned for a played match
+func scorePrediction(prediction Prediction, match *Match) int {
+	if !match.IsPlayed {
+		return 0
+	}
+
+	if prediction.HomeGoals == match.HomeGoals && prediction.AwayGoals == match.AwayGoals {
+		return exactScorePoints
+	}
+
+	if matchOutcome(prediction.HomeGoals, prediction.AwayGoals) == matchOutcome(match.HomeGoals, match.AwayGoals) {
+		return correctResultPoints
+	}
+	return 0
+}
+
+// reduce a score to "H", "D" or "A"
+func matchOutcome(homeGoals, awayGoals int) string {
+	if homeGoals > awayGoals {
+		return "H"
+	} else if homeGoals == awayGoals {
+		return "D"
+	}
+	return "A"
+}
+
+// find a fixture by week and teams
+func (l *League) findMatch(week int, homeTeam, awayTeam string) *Match {
+	if week < 1 || week > len(l.Fixtures) {
+		return nil
+	}
+	for i := range l.Fixtures[week-1] {
+		match := &l.Fixtures[week-1][i]
+		if match.HomeTeam.Name == homeTeam && match.AwayTeam.Name == awayTeam {
+			return match
+		}
+	}
+	return nil
+}
+
+// PredictorLeaderboard scores every prediction against the current results.
+// scores are worked out fresh each time so edited results are picked up too
+func (l *League) PredictorLeaderboard(predictors []Predictor, predictions []Prediction) []PredictorStanding {
+	standings := make(map[int64]*PredictorStanding)
+	for _, p := range predictors {
+		standings[p.ID] = &PredictorStanding{Name: p.Name}
+	}
+
+	for _, prediction := range predictions {
+		standing, exists := standings[prediction.PredictorID]
+		if !exists {
+			continue
+		}
+		match := l.findMatch(prediction.Week, prediction.HomeTeam, prediction.AwayTeam)
+		if match == nil || !match.IsPlayed {
+			continue
+		}
+
+		standing.Scored++
+		switch scorePrediction(prediction, match) {
+		case exactScorePoints:
+			standing.ExactScores++
+			standing.Points += exactScorePoints
+		case correctResultPoints:
+			standing.CorrectResults++
+			standing.Points += correctResultPoints
+		}
+	}
+
+	var leaderboard []PredictorStanding
+	for _, standing := range standings {
+		leaderboard = append(leaderboard, *standing)
+	}
+	sort.Slice(leaderboard, func(i, j int) bool {
+		if leaderboard[i].Points != leaderboard[j].Points {
+			return leaderboard[i].Points > leaderboard[j].Points
+		}
+		if leaderboard[i].ExactScores != leaderboard[j].ExactScores {
+			return leaderboard[i].ExactScores > leaderboard[j].ExactScores
+		}
+		return leaderboard[i].Name < leaderboard[j].Name
+	})
+	return leaderboard
+}
+
+// generatePredictorTable creates the predictor league leaderboard
+func (g *GUI) generatePredictorTable(league *League) string {
+	if db == nil || league.ID == 0 {
+		return ""
+	}
+
+	predictors, err := db.GetPredictors()
+	if err != nil || len(predictors) == 0 {
+		return ""
+	}
+	predictions, err := db.GetPredictions(league.ID)
+	if err != nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Predictor League\n")
+	sb.WriteString("---------------------------------\n")
+	sb.WriteString(fmt.Sprintf("%-15s %3s  %3s  %3s  %3s\n", "Name", "P", "EX", "RES", "PTS"))
+	for _, standing := range league.PredictorLeaderboard(predictors, predictions) {
+		sb.WriteString(fmt.Sprintf("%-15.15s %3d  %3d  %3d  %3d\n",
+			standing.Name,
+			standing.Scored,
+			standing.ExactScores,
+			standing.CorrectResults,
+			standing.Points))
+	}
+	return sb.String()
+}
+
+// enterPredictions opens a dialog for predicting the upcoming week's scores
+func (g *GUI) enterPredictions() {
+	// take a copy of the fixtures we're predicting
+	var week int
+	var leagueID int64
+	var weekMatches []Match
+	g.engine.Read(func(league *League) {
+		week = league.upcomingWeek()
+		leagueID = league.ID
+		for _, match := range league.weekMatches(week) {
+			if !match.IsPlayed {
+				weekMatches = append(weekMatches, *match)
+			}
+		}
+	})
+	if db == nil || leagueID == 0 || len(weekMatches) == 0 {
+		return
+	}
+
+	var names []string
+	if predictors, err := db.GetPredictors(); err == nil {
+		for _, p := range predictors {
+			names = append(names, p.Name)
+		}
+	}
+	nameEntry := widget.NewSelectEntry(names)
+	nameEntry.SetPlaceHolder("Your name")
+
+	// one pair of entries per fixture
+	maxGoals := g.engine.Settings().MaxGoals
+	validateGoals := goalsValidator(maxGoals)
+	homeEntries := make([]*widget.Entry, len(weekMatches))
+	awayEntries := make([]*widget.Entry, len(weekMatches))
+	grid := container.NewGridWithColumns(3)
+	for i, match := range weekMatches {
+		homeEntries[i] = widget.NewEntry()
+		homeEntries[i].Validator = validateGoals
+		awayEntries[i] = widget.NewEntry()
+		awayEntries[i].Validator = validateGoals
+		grid.Add(widget.NewLabel(fmt.Sprintf("%s vs %s", match.HomeTeam.Name, match.AwayTeam.Name)))
+		grid.Add(homeEntries[i])
+		grid.Add(awayEntries[i])
+	}
+
+	errorLabel := widget.NewLabel("")
+	content := container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("Predictions for Week %d", week)),
+		nameEntry,
+		grid,
+		errorLabel,
+	)
+
+	dialog := widget.NewModalPopUp(content, g.window.Canvas())
+
+	buttons := container.NewHBox(
+		widget.NewButton("Save", func() {
+			// play all could have got there first
+			kickedOff := false
+			g.engine.Read(func(league *League) {
+				kickedOff = league.ID != leagueID || league.upcomingWeek() != week
+			})
+			if kickedOff {
+				errorLabel.SetText(fmt.Sprintf("Week %d has already kicked off", week))
+				return
+			}
+
+			predictor, err := db.GetOrCreatePredictor(strings.TrimSpace(nameEntry.Text))
+			if err != nil {
+				errorLabel.SetText("Please enter your name")
+				return
+			}
+
+			for i, match := range weekMatches {
+				// leave fixtures without a prediction alone
+				if homeEntries[i].Text == "" || awayEntries[i].Text == "" {
+					continue
+				}
+				if validateGoals(homeEntries[i].Text) != nil || validateGoals(awayEntries[i].Text) != nil {
+					errorLabel.SetText(fmt.Sprintf("Scores must be between 0 and %d", maxGoals))
+					return
+				}
+
+				// saved against the fixture's own week, a rearranged match is still found there
+				prediction := &Prediction{
+					PredictorID: predictor.ID,
+					Week:        match.Week,
+					HomeTeam:    match.HomeTeam.Name,
+					AwayTeam:    match.AwayTeam.Name,
+				}
+				fmt.Sscanf(homeEntries[i].Text, "%d", &prediction.HomeGoals)
+				fmt.Sscanf(awayEntries[i].Text, "%d", &prediction.AwayGoals)
+
+				if err := db.SavePrediction(leagueID, prediction); err != nil {
+					errorLabel.SetText(fmt.Sprintf("Failed to save prediction: %v", err))
+					return
+				}
+			}
+
+			dialog.Hide()
+			g.refreshDisplay()
+		}),
+		widget.NewButton("Cancel", func() {
+			dialog.Hide()
+		}),
+	)
+
+	dialog.Content = container.NewVBox(content, buttons)
+	dialog.Resize(fyne.NewSize(500, 300))
+	dialog.Show()
+}
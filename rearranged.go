@@ -0,0 +1,377 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// what can happen to a match other than being played when it was meant to be
+const (
+	MatchPostponed = "postponed" // moved to a later week or a midweek slot
+	MatchAbandoned = "abandoned" // stopped part way, the result's void and it's played again
+	MatchWalkover  = "walkover"  // awarded to one side without being played
+)
+
+// goals a walkover is awarded by
+const walkoverGoals = 3
+
+// the week the match is played in, which isn't its own if it was rearranged
+func (m *Match) dueWeek() int {
+	if m.PlayWeek > 0 {
+		return m.PlayWeek
+	}
+	return m.Week
+}
+
+// where the match is played, "Week 5" or "Week 5 midweek"
+func (m *Match) slotName() string {
+	if m.Midweek {
+		return fmt.Sprintf("Week %d midweek", m.dueWeek())
+	}
+	return fmt.Sprintf("Week %d", m.dueWeek())
+}
+
+// a note on what happened to the match for lists of results and fixtures, "" if nothing did
+func (m *Match) statusNote() string {
+	switch m.Status {
+	case MatchWalkover:
+		return " (walkover)"
+	case MatchAbandoned:
+		if m.IsPlayed {
+			return " (replayed)"
+		}
+		return fmt.Sprintf(" (abandoned, replay %s)", strings.ToLower(m.slotName()))
+	case MatchPostponed:
+		if m.IsPlayed {
+			return " (rearranged)"
+		}
+		return fmt.Sprintf(" (postponed to %s)", strings.ToLower(m.slotName()))
+	}
+	return ""
+}
+
+// the matches played in a week: games rearranged into its midweek first, then the weekend
+// round, leaving out anything moved away from it and taking in anything moved to it
+func (l *League) weekMatches(week int) []*Match {
+	var midweek, weekend []*Match
+	for w := range l.Fixtures {
+		for i := range l.Fixtures[w] {
+			match := &l.Fixtures[w][i]
+			if match.dueWeek() != week {
+				continue
+			}
+			if match.Midweek {
+				midweek = append(midweek, match)
+			} else {
+				weekend = append(weekend, match)
+			}
+		}
+	}
+	return append(midweek, weekend...)
+}
+
+// every match still to be played, in the order they now fall
+func (l *League) unplayedMatches() []*Match {
+	var matches []*Match
+	for week := 1; week <= len(l.Fixtures); week++ {
+		for _, match := range l.weekMatches(week) {
+			if !match.IsPlayed {
+				matches = append(matches, match)
+			}
+		}
+	}
+	return matches
+}
+
+// how many matches there are in the season
+func (l *League) fixtureCount() int {
+	count := 0
+	for _, week := range l.Fixtures {
+		count += len(week)
+	}
+	return count
+}
+
+// games in hand on whoever has played the most, by team
+func (l *League) gamesInHand() map[string]int {
+	most := 0
+	for _, t := range l.Teams {
+		most = max(most, t.Played)
+	}
+	inHand := make(map[string]int)
+	for _, t := range l.Teams {
+		inHand[t.Name] = most - t.Played
+	}
+	return inHand
+}
+
+// check a match can be moved to week, or its midweek, and find when it kicks off there.
+// it has to be a week still to come with a free date, and neither side can already be
+// playing in that slot
+func (l *League) checkSlot(match *Match, week int, midweek bool) (time.Time, error) {
+	if week < l.upcomingWeek() || week > len(l.Fixtures) {
+		return time.Time{}, fmt.Errorf("week has to be between %d and %d", l.upcomingWeek(), len(l.Fixtures))
+	}
+	if midweek && week <= len(l.Rounds) && l.Rounds[week-1].Midweek {
+		return time.Time{}, fmt.Errorf("week %d is a midweek round already, move it into the round itself", week)
+	}
+	kickoff, err := l.slotKickoff(week, midweek)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, other := range l.weekMatches(week) {
+		if other == match || other.Midweek != midweek {
+			continue
+		}
+		for _, team := range []*Team{match.HomeTeam, match.AwayTeam} {
+			if other.HomeTeam == team || other.AwayTeam == team {
+				slot := "weekend"
+				if midweek {
+					slot = "midweek"
+				}
+				return time.Time{}, fmt.Errorf("%s already play %s v %s that %s", team.Name, other.HomeTeam.Name, other.AwayTeam.Name, slot)
+			}
+		}
+	}
+	return kickoff, nil
+}
+
+// move the slot a match is played in
+func (m *Match) moveTo(week int, midweek bool) {
+	m.PlayWeek = week
+	if week == m.Week && !midweek {
+		m.PlayWeek = 0 // back where it started
+	}
+	m.Midweek = midweek
+}
+
+// postpone a match that hasn't been played to a later week, or a midweek slot
+func (l *League) postponeMatch(match *Match, week int, midweek bool) error {
+	if match.IsPlayed {
+		return fmt.Errorf("%s v %s has been played, abandon it to play it again", match.HomeTeam.Name, match.AwayTeam.Name)
+	}
+	if week == match.dueWeek() && midweek == match.Midweek {
+		return fmt.Errorf("%s v %s is already in %s", match.HomeTeam.Name, match.AwayTeam.Name, strings.ToLower(match.slotName()))
+	}
+	kickoff, err := l.checkSlot(match, week, midweek)
+	if err != nil {
+		return err
+	}
+	match.moveTo(week, midweek)
+	match.Kickoff = kickoff
+	if match.Status != MatchAbandoned {
+		match.Status = MatchPostponed
+	}
+	return nil
+}
+
+// abandon a played match: the result is void and it's replayed in week
+func (l *League) abandonMatch(match *Match, week int, midweek bool) error {
+	if !match.IsPlayed || match.Status == MatchWalkover {
+		return fmt.Errorf("only a match that was played can be abandoned")
+	}
+	kickoff, err := l.checkSlot(match, week, midweek)
+	if err != nil {
+		return err
+	}
+	match.moveTo(week, midweek)
+	match.Kickoff = kickoff
+	match.Status = MatchAbandoned
+	match.HomeGoals, match.AwayGoals = 0, 0
+	match.HomePenalties, match.AwayPenalties = 0, 0
+	match.IsPlayed = false
+	match.IsFixed = false
+	match.Events = nil
+	match.collectIncidents()
+	return nil
+}
+
+// award a match that hasn't been played to winner. it counts straight away
+func (l *League) awardWalkover(match *Match, winner string) error {
+	if match.IsPlayed {
+		return fmt.Errorf("%s v %s has been played", match.HomeTeam.Name, match.AwayTeam.Name)
+	}
+	switch winner {
+	case match.HomeTeam.Name:
+		match.HomeGoals, match.AwayGoals = walkoverGoals, 0
+	case match.AwayTeam.Name:
+		match.HomeGoals, match.AwayGoals = 0, walkoverGoals
+	default:
+		return fmt.Errorf("%s isn't playing in %s v %s", winner, match.HomeTeam.Name, match.AwayTeam.Name)
+	}
+	match.Status = MatchWalkover
+	match.HomePenalties, match.AwayPenalties = 0, 0
+	match.IsPlayed = true
+	match.IsFixed = true
+	match.Events = nil
+	match.collectIncidents()
+	return nil
+}
+
+// the rearranged matches for the top of the dialog, blank if there aren't any
+func generateRearrangedList(league *League) string {
+	var matches []*Match
+	for w := range league.Fixtures {
+		for i := range league.Fixtures[w] {
+			if match := &league.Fixtures[w][i]; match.Status != "" {
+				matches = append(matches, match)
+			}
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].dueWeek() < matches[j].dueWeek()
+	})
+
+	var sb strings.Builder
+	for _, match := range matches {
+		line := fmt.Sprintf("Week %-2d %s v %s", match.Week, match.HomeTeam.Name, match.AwayTeam.Name)
+		if match.IsPlayed {
+			line += fmt.Sprintf(" %d - %d", match.HomeGoals, match.AwayGoals)
+		}
+		sb.WriteString(line + match.statusNote() + "\n")
+	}
+	return sb.String()
+}
+
+// showRearrange opens the dialog for postponing a match, abandoning one to be replayed
+// or awarding a walkover. selected is the fixtureKey of the match to start on, "" for none
+func (g *GUI) showRearrange(selected string) {
+	// every match that can still be rearranged, by label
+	var labels []string
+	matches := make(map[string]Match)
+	selectedLabel := ""
+	var weeks []string
+	var rearranged string
+	g.engine.Read(func(league *League) {
+		for week := 1; week <= len(league.Fixtures); week++ {
+			for _, match := range league.weekMatches(week) {
+				if match.Status == MatchWalkover {
+					continue // nothing to play or replay
+				}
+				label := fmt.Sprintf("%s: %s v %s", match.slotName(), match.HomeTeam.Name, match.AwayTeam.Name)
+				if match.IsPlayed {
+					label += fmt.Sprintf(" %d - %d", match.HomeGoals, match.AwayGoals)
+				}
+				labels = append(labels, label)
+				matches[label] = *match
+				if fixtureKey(match.Week, match.HomeTeam.Name, match.AwayTeam.Name) == selected {
+					selectedLabel = label
+				}
+			}
+		}
+		for week := league.upcomingWeek(); week <= len(league.Fixtures); week++ {
+			weeks = append(weeks, fmt.Sprintf("Week %d", week))
+		}
+		rearranged = generateRearrangedList(league)
+	})
+	if len(labels) == 0 {
+		return
+	}
+	if rearranged == "" {
+		rearranged = "Nothing rearranged yet\n"
+	}
+
+	const postpone, abandon, walkover = "Postpone", "Abandon and replay", "Award walkover"
+	weekSelect := widget.NewSelect(weeks, nil)
+	weekSelect.PlaceHolder = "Week..."
+	midweekCheck := widget.NewCheck("Midweek, before that week's round", nil)
+	winnerSelect := widget.NewSelect(nil, nil)
+	winnerSelect.PlaceHolder = "Awarded to..."
+	action := widget.NewRadioGroup(nil, nil)
+	action.Horizontal = true
+	statusLabel := widget.NewLabel("")
+
+	// what can be done depends on whether the match has been played
+	matchSelect := widget.NewSelect(labels, func(label string) {
+		match := matches[label]
+		if match.IsPlayed {
+			action.Options = []string{abandon}
+		} else {
+			action.Options = []string{postpone, walkover}
+		}
+		action.SetSelected(action.Options[0])
+		action.Refresh()
+		winnerSelect.Options = []string{match.HomeTeam.Name, match.AwayTeam.Name}
+		winnerSelect.ClearSelected()
+		statusLabel.SetText("")
+	})
+	matchSelect.PlaceHolder = "Match..."
+	action.OnChanged = func(selected string) {
+		if selected == walkover {
+			weekSelect.Disable()
+			midweekCheck.Disable()
+			winnerSelect.Enable()
+		} else {
+			weekSelect.Enable()
+			midweekCheck.Enable()
+			winnerSelect.Disable()
+		}
+	}
+	if selectedLabel != "" {
+		matchSelect.SetSelected(selectedLabel)
+	}
+
+	var dialog *widget.PopUp
+	applyButton := widget.NewButton("Apply", func() {
+		match, ok := matches[matchSelect.Selected]
+		if !ok {
+			statusLabel.SetText("Pick a match")
+			return
+		}
+		var err error
+		switch action.Selected {
+		case walkover:
+			if winnerSelect.Selected == "" {
+				statusLabel.SetText("Pick who it's awarded to")
+				return
+			}
+			err = g.engine.AwardWalkover(match.Week, match.HomeTeam.Name, match.AwayTeam.Name, winnerSelect.Selected)
+		case postpone, abandon:
+			week, convErr := strconv.Atoi(strings.TrimPrefix(weekSelect.Selected, "Week "))
+			if convErr != nil {
+				statusLabel.SetText("Pick the week it's played in")
+				return
+			}
+			if action.Selected == postpone {
+				err = g.engine.PostponeMatch(match.Week, match.HomeTeam.Name, match.AwayTeam.Name, week, midweekCheck.Checked)
+			} else {
+				err = g.engine.AbandonMatch(match.Week, match.HomeTeam.Name, match.AwayTeam.Name, week, midweekCheck.Checked)
+			}
+		default:
+			statusLabel.SetText("Pick what happened")
+			return
+		}
+		if err != nil {
+			statusLabel.SetText(err.Error())
+			return
+		}
+		dialog.Hide()
+	})
+
+	content := container.NewVBox(
+		widget.NewLabelWithStyle("Rearranged Matches", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle(rearranged, fyne.TextAlignLeading, fyne.TextStyle{Monospace: true}),
+		matchSelect,
+		action,
+		container.NewGridWithColumns(2,
+			widget.NewLabel("Played in:"), weekSelect,
+			widget.NewLabel(""), midweekCheck,
+			widget.NewLabel("Walkover to:"), winnerSelect,
+		),
+		widget.NewLabel(fmt.Sprintf("A walkover is a %d - 0 win and counts straight away.", walkoverGoals)),
+		statusLabel,
+		container.NewHBox(applyButton, widget.NewButton("Close", func() {
+			dialog.Hide()
+		})),
+	)
+	dialog = widget.NewModalPopUp(content, g.window.Canvas())
+	dialog.Resize(fyne.NewSize(600, 460))
+	dialog.Show()
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGamesInHand(t *testing.T) {
+	tests := []struct {
+		name   string
+		played map[string]int
+		want   map[string]int
+	}{
+		{"before kick off", map[string]int{"Arsenal": 0, "Chelsea": 0}, map[string]int{"Arsenal": 0, "Chelsea": 0}},
+		{"all level", map[string]int{"Arsenal": 5, "Chelsea": 5, "Everton": 5}, map[string]int{"Arsenal": 0, "Chelsea": 0, "Everton": 0}},
+		{"one postponed", map[string]int{"Arsenal": 5, "Chelsea": 4, "Everton": 5}, map[string]int{"Arsenal": 0, "Chelsea": 1, "Everton": 0}},
+		{"on whoever has played most", map[string]int{"Arsenal": 6, "Chelsea": 4, "Everton": 5}, map[string]int{"Arsenal": 0, "Chelsea": 2, "Everton": 1}},
+		{"no teams", map[string]int{}, map[string]int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &League{}
+			for name, played := range tt.played {
+				l.Teams = append(l.Teams, &Team{Name: name, Played: played})
+			}
+			if got := l.gamesInHand(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("gamesInHand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckSlot(t *testing.T) {
+	e := testEngine(t)
+	e.SimulateWeek()
+
+	tests := []struct {
+		name    string
+		week    int
+		midweek bool
+		want    string // the kickoff, "" if the slot can't be used
+	}{
+		{"week already played", 1, false, ""},
+		{"past the end of the season", 19, false, ""},
+		{"into a midweek round's midweek", 4, true, ""},
+		{"straight after a midweek round", 5, true, ""},
+		{"tuesday in a break, so wednesday", 6, true, "2025-09-10 20:00"},
+		{"free midweek", 8, true, "2025-09-23 19:45"},
+		{"both sides already playing that weekend", 8, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e.Read(func(l *League) {
+				kickoff, err := l.checkSlot(&l.Fixtures[2][0], tt.week, tt.midweek)
+				switch {
+				case tt.want == "" && err == nil:
+					t.Errorf("week %d (midweek %v) allowed at %s", tt.week, tt.midweek, kickoff.Format(kickoffFormat))
+				case tt.want != "" && err != nil:
+					t.Errorf("week %d (midweek %v): %v", tt.week, tt.midweek, err)
+				case tt.want != "" && kickoff.Format(kickoffFormat) != tt.want:
+					t.Errorf("week %d (midweek %v) kicks off %s, want %s", tt.week, tt.midweek, kickoff.Format(kickoffFormat), tt.want)
+				}
+			})
+		})
+	}
+}
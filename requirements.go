@@ -0,0 +1,328 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// simulations used for the "typically enough" figure
+const requirementSimulations = 2000
+
+// biggest group of rivals we check result by result. bigger groups fall back
+// to each rival's maximum points, which can ask for a little more than needed
+const maxExactRivals = 2
+
+// Requirements is what a team needs from its remaining fixtures to finish in a position
+type Requirements struct {
+	Team         string
+	Position     int
+	Points       int // points so far
+	GamesLeft    int
+	Available    int  // points left to play for
+	Guaranteed   int  // points that make the position certain, whatever else happens
+	CanGuarantee bool // false if even that isn't enough without help
+	Typical      int  // points that would usually be enough
+	MagicNumber  int  // points won plus points the challengers drop that clinch it, 0 or less once clinched
+	OutOfReach   bool // even winning every game wouldn't be enough
+}
+
+// matches that haven't been played yet
+func (l *League) remainingMatches() []*Match {
+	return l.unplayedMatches()
+}
+
+// Requirements works out what team needs to finish in position or higher
+func (l *League) Requirements(team *Team, position int) Requirements {
+	remaining := l.remainingMatches()
+	req := Requirements{Team: team.Name, Position: position, Points: team.Points}
+	for _, match := range remaining {
+		if match.HomeTeam == team || match.AwayTeam == team {
+			req.GamesLeft++
+		}
+	}
+	req.Available = settings.Points.maxPerMatch() * req.GamesLeft
+
+	var rivals []*Team
+	for _, t := range l.Teams {
+		if t != team {
+			rivals = append(rivals, t)
+		}
+	}
+	if position > len(rivals) {
+		// bottom of the table is as low as you can go
+		req.CanGuarantee = true
+		return req
+	}
+
+	// the most each rival could finish on, highest first
+	var rivalMax []int
+	ahead := 0
+	for _, r := range rivals {
+		games := 0
+		for _, match := range remaining {
+			if match.HomeTeam == r || match.AwayTeam == r {
+				games++
+			}
+		}
+		rivalMax = append(rivalMax, r.Points+settings.Points.maxPerMatch()*games)
+		if r.Points > team.Points+req.Available {
+			ahead++
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(rivalMax)))
+
+	req.OutOfReach = ahead >= position
+	req.MagicNumber = rivalMax[position-1] - team.Points + 1
+	req.Guaranteed, req.CanGuarantee = l.guaranteedPoints(team, rivals, position, remaining, rivalMax[position-1])
+	req.Typical = l.typicalPoints(team, position)
+	return req
+}
+
+// the fewest points from the remaining fixtures that keep team above all but position-1 of its
+// rivals however the other results go. false if no number of points is enough on its own
+func (l *League) guaranteedPoints(team *Team, rivals []*Team, position int, remaining []*Match, kthRivalMax int) (int, bool) {
+	gamesLeft := 0
+	for _, match := range remaining {
+		if match.HomeTeam == team || match.AwayTeam == team {
+			gamesLeft++
+		}
+	}
+	available := settings.Points.maxPerMatch() * gamesLeft
+
+	if position > maxExactRivals {
+		// finish above the position-th best maximum and nobody else can catch us
+		needed := kthRivalMax - team.Points + 1
+		if needed < 0 {
+			needed = 0
+		}
+		return needed, needed <= available
+	}
+
+	// any group of position rivals all finishing level or above us pushes us out.
+	// for each group, find every points total of ours that can happen alongside that
+	threatened := make(map[int]bool)
+	reachable := make(map[int]bool)
+	for _, group := range rivalGroups(rivals, position) {
+		for points, threat := range l.threatenedTotals(team, group, remaining, available) {
+			reachable[points] = true
+			if threat {
+				threatened[points] = true
+			}
+		}
+	}
+
+	highestThreat := -1
+	for points := range threatened {
+		if points > highestThreat {
+			highestThreat = points
+		}
+	}
+	for points := highestThreat + 1; points <= available; points++ {
+		if reachable[points] {
+			return points, true
+		}
+	}
+	return 0, false
+}
+
+// every group of size rivals
+func rivalGroups(rivals []*Team, size int) [][]*Team {
+	if size == 0 {
+		return [][]*Team{nil}
+	}
+	var groups [][]*Team
+	for i := range rivals {
+		for _, rest := range rivalGroups(rivals[i+1:], size-1) {
+			groups = append(groups, append([]*Team{rivals[i]}, rest...))
+		}
+	}
+	return groups
+}
+
+// where a search of the remaining results has got to: the team's points and
+// what each rival in the group has picked up (capped, past that it makes no difference)
+type requirementState struct {
+	points int
+	gained [maxExactRivals]int
+}
+
+// go through every way the remaining matches involving team or the group can go. for each
+// points total team can finish with, says whether the whole group can end up level or above
+func (l *League) threatenedTotals(team *Team, group []*Team, remaining []*Match, available int) map[int]bool {
+	caps := make([]int, len(group))
+	for i, r := range group {
+		caps[i] = team.Points + available - r.Points
+		if caps[i] < 0 {
+			caps[i] = 0
+		}
+	}
+	groupIndex := func(t *Team) int {
+		for i, r := range group {
+			if r == t {
+				return i
+			}
+		}
+		return -1
+	}
+
+	outcomes := settings.Points.outcomes()
+	states := map[requirementState]bool{{}: true}
+	for _, match := range remaining {
+		homeIndex, awayIndex := groupIndex(match.HomeTeam), groupIndex(match.AwayTeam)
+		homeIsTeam, awayIsTeam := match.HomeTeam == team, match.AwayTeam == team
+		if homeIndex < 0 && awayIndex < 0 && !homeIsTeam && !awayIsTeam {
+			continue // doesn't affect anyone we care about
+		}
+
+		next := make(map[requirementState]bool)
+		for state := range states {
+			for _, result := range outcomes {
+				s := state
+				if homeIsTeam {
+					s.points += result[0]
+				} else if homeIndex >= 0 {
+					s.gained[homeIndex] = min(s.gained[homeIndex]+result[0], caps[homeIndex])
+				}
+				if awayIsTeam {
+					s.points += result[1]
+				} else if awayIndex >= 0 {
+					s.gained[awayIndex] = min(s.gained[awayIndex]+result[1], caps[awayIndex])
+				}
+				next[s] = true
+			}
+		}
+		states = next
+	}
+
+	totals := make(map[int]bool)
+	for state := range states {
+		threat := true
+		for i, r := range group {
+			if r.Points+state.gained[i] < team.Points+state.points {
+				threat = false
+				break
+			}
+		}
+		totals[state.points] = totals[state.points] || threat
+	}
+	return totals
+}
+
+// points from the remaining fixtures that would usually be enough: the median of what it takes
+// to finish above the position-th best rival over many simulated seasons
+func (l *League) typicalPoints(team *Team, position int) int {
+	var needed []int
+	r := newSimulationRand()
+	for sim := 0; sim < requirementSimulations; sim++ {
+		teamsCopy := l.copyTeams()
+		l.simulateRemaining(teamsCopy, nil, r)
+
+		var rivalPoints []int
+		for _, t := range teamsCopy {
+			if t.Name != team.Name {
+				rivalPoints = append(rivalPoints, t.Points)
+			}
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(rivalPoints)))
+		need := rivalPoints[position-1] + 1 - team.Points
+		if need < 0 {
+			need = 0
+		}
+		needed = append(needed, need)
+	}
+	sort.Ints(needed)
+	return needed[len(needed)/2]
+}
+
+// generateRequirementsReport writes out what a team needs
+func generateRequirementsReport(req Requirements) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("What does %s need? (to finish %s or higher)\n", req.Team, positionName(req.Position)))
+	sb.WriteString("--------------------------------------------------\n")
+	sb.WriteString(fmt.Sprintf("%-22s %d (%d games left, %d points available)\n", "Points so far:", req.Points, req.GamesLeft, req.Available))
+
+	switch {
+	case req.OutOfReach:
+		sb.WriteString("Out of reach - even winning every game won't be enough\n")
+		return sb.String()
+	case req.CanGuarantee && req.Guaranteed == 0:
+		sb.WriteString("Already guaranteed, whatever happens\n")
+		return sb.String()
+	case req.CanGuarantee:
+		sb.WriteString(fmt.Sprintf("%-22s %d more points, whatever else happens\n", "Guaranteed with:", req.Guaranteed))
+	default:
+		sb.WriteString("Can't be guaranteed    needs other results to go their way\n")
+	}
+
+	if req.Typical > req.Available {
+		sb.WriteString(fmt.Sprintf("%-22s %d more points - more than they can get\n", "Typically needs:", req.Typical))
+	} else {
+		sb.WriteString(fmt.Sprintf("%-22s %d more points\n", "Typically enough:", req.Typical))
+	}
+	sb.WriteString(fmt.Sprintf("%-22s %d (points won plus points the challengers drop)\n", "Magic number:", req.MagicNumber))
+	return sb.String()
+}
+
+// showRequirements opens the "what does my team need?" calculator
+func (g *GUI) showRequirements() {
+	var teamNames []string
+	g.engine.Read(func(league *League) {
+		for _, team := range league.Teams {
+			teamNames = append(teamNames, team.Name)
+		}
+	})
+	sort.Strings(teamNames)
+
+	var positions []string
+	for position := 1; position < len(teamNames); position++ {
+		positions = append(positions, positionName(position))
+	}
+
+	report := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+	teamSelect := widget.NewSelect(teamNames, nil)
+	teamSelect.PlaceHolder = "Team..."
+	positionSelect := widget.NewSelect(positions, nil)
+	positionSelect.SetSelectedIndex(0)
+
+	// work it out in the background, the search can take a moment early in the season
+	update := func(string) {
+		teamName, position := teamSelect.Selected, positionSelect.SelectedIndex()+1
+		if teamName == "" || position < 1 {
+			return
+		}
+		report.SetText("Working it out...")
+		go func() {
+			var text string
+			g.engine.Snapshot(func(league *League) {
+				if team := findTeam(league.Teams, teamName); team != nil {
+					text = generateRequirementsReport(league.Requirements(team, position))
+				}
+			})
+			fyne.Do(func() {
+				// only if they haven't picked something else in the meantime
+				if teamSelect.Selected == teamName && positionSelect.SelectedIndex()+1 == position {
+					report.SetText(text)
+				}
+			})
+		}()
+	}
+	teamSelect.OnChanged = update
+	positionSelect.OnChanged = update
+
+	content := container.NewVBox(
+		widget.NewLabel("What does my team need?"),
+		container.NewHBox(teamSelect, widget.NewLabel("to finish"), positionSelect),
+		report,
+	)
+	dialog := widget.NewModalPopUp(content, g.window.Canvas())
+	dialog.Content = container.NewVBox(content, widget.NewButton("Close", func() {
+		dialog.Hide()
+	}))
+	dialog.Resize(fyne.NewSize(600, 300))
+	dialog.Show()
+}
@@ -0,0 +1,86 @@
+package main
+
+import "testing"
+
+// a league of teams on the given points with the given matches still to play, all in week 1
+func requirementsLeague(points map[string]int, remaining [][2]string) *League {
+	l := &League{}
+	for _, name := range []string{"Arsenal", "Brentford", "Chelsea", "Everton"} {
+		l.Teams = append(l.Teams, &Team{Name: name, Points: points[name], BaseStrength: 75, CurrentStrength: 75, Availability: 1, Form: make([]string, 5)})
+	}
+	if len(remaining) > 0 {
+		var week []Match
+		for _, m := range remaining {
+			week = append(week, Match{HomeTeam: findTeam(l.Teams, m[0]), AwayTeam: findTeam(l.Teams, m[1]), Week: 1})
+		}
+		l.Fixtures = [][]Match{week}
+	}
+	return l
+}
+
+func TestRequirements(t *testing.T) {
+	settings = builtinSettings()
+
+	finished := map[string]int{"Arsenal": 10, "Brentford": 8, "Chelsea": 5, "Everton": 2}
+	lastWeek := map[string]int{"Arsenal": 10, "Brentford": 9, "Chelsea": 4, "Everton": 0}
+	lastFixtures := [][2]string{{"Arsenal", "Brentford"}, {"Chelsea", "Everton"}}
+
+	tests := []struct {
+		name         string
+		points       map[string]int
+		remaining    [][2]string
+		team         string
+		position     int
+		gamesLeft    int
+		available    int
+		guaranteed   int
+		canGuarantee bool
+		magicNumber  int
+		outOfReach   bool
+	}{
+		{"champions already", finished, nil, "Arsenal", 1, 0, 0, 0, true, -1, false},
+		{"can't catch the leaders", finished, nil, "Everton", 1, 0, 0, 0, false, 9, true},
+		{"bottom is always possible", finished, nil, "Everton", 4, 0, 0, 0, true, 0, false},
+		// a draw in the decider is enough, even though the magic number asks for a win
+		{"leaders need a draw", lastWeek, lastFixtures, "Arsenal", 1, 1, 3, 1, true, 3, false},
+		{"second have to win", lastWeek, lastFixtures, "Brentford", 1, 1, 3, 3, true, 5, false},
+		{"two out of reach", lastWeek, lastFixtures, "Chelsea", 2, 1, 3, 0, false, 9, true},
+		{"third already safe", lastWeek, lastFixtures, "Chelsea", 3, 1, 3, 0, true, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := requirementsLeague(tt.points, tt.remaining)
+			req := l.Requirements(findTeam(l.Teams, tt.team), tt.position)
+			if req.GamesLeft != tt.gamesLeft || req.Available != tt.available {
+				t.Errorf("games left %d, available %d, want %d and %d", req.GamesLeft, req.Available, tt.gamesLeft, tt.available)
+			}
+			if req.Guaranteed != tt.guaranteed || req.CanGuarantee != tt.canGuarantee {
+				t.Errorf("guaranteed %d (%v), want %d (%v)", req.Guaranteed, req.CanGuarantee, tt.guaranteed, tt.canGuarantee)
+			}
+			if tt.position < len(l.Teams) && req.MagicNumber != tt.magicNumber {
+				t.Errorf("magic number %d, want %d", req.MagicNumber, tt.magicNumber)
+			}
+			if req.OutOfReach != tt.outOfReach {
+				t.Errorf("out of reach %v, want %v", req.OutOfReach, tt.outOfReach)
+			}
+		})
+	}
+}
+
+func TestRequirementsWithBonusPoints(t *testing.T) {
+	settings = builtinSettings()
+	settings.Points = PointsRules{Win: 4, Draw: 2, GoalsBonus: 1, GoalsBonusAt: 4, LosingBonus: 1}
+	defer func() { settings = builtinSettings() }()
+
+	l := requirementsLeague(map[string]int{"Arsenal": 20, "Brentford": 16}, [][2]string{{"Arsenal", "Brentford"}})
+	req := l.Requirements(findTeam(l.Teams, "Arsenal"), 1)
+	// five points a game with the bonus, and brentford can get to 21
+	if req.Available != 5 || req.MagicNumber != 2 {
+		t.Errorf("available %d, magic number %d, want 5 and 2", req.Available, req.MagicNumber)
+	}
+	// losing 4-5 still gives two points and leaves brentford a point behind,
+	// but a point from losing 3-4 doesn't
+	if req.Guaranteed != 2 || !req.CanGuarantee {
+		t.Errorf("guaranteed %d (%v), want 2 (true)", req.Guaranteed, req.CanGuarantee)
+	}
+}
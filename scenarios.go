@@ -0,0 +1,331 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// how many seasons to simulate per scenario when comparing
+const scenarioSimulations = 5000
+
+// biggest strength tweak a scenario can give a team, either way
+const maxStrengthAdjustment = 20
+
+// Scenario is a named set of pinned results and strength tweaks to test against the real league
+type Scenario struct {
+	ID        int64
+	Name      string
+	Pins      []ScenarioPin
+	Strengths map[string]int // strength adjustment by team name
+}
+
+// a result pinned by a scenario
+type ScenarioPin struct {
+	Week      int
+	HomeTeam  string
+	AwayTeam  string
+	HomeGoals int
+	AwayGoals int
+}
+
+// key for looking a fixture up in a set of pins
+func fixtureKey(week int, homeTeam, awayTeam string) string {
+	return fmt.Sprintf("%d:%s:%s", week, homeTeam, awayTeam)
+}
+
+// the scenario's pins by fixture
+func (s *Scenario) pinMap() map[string]ScenarioPin {
+	pins := make(map[string]ScenarioPin)
+	for _, pin := range s.Pins {
+		pins[fixtureKey(pin.Week, pin.HomeTeam, pin.AwayTeam)] = pin
+	}
+	return pins
+}
+
+// the league's pinned results, for saving as a scenario
+func (l *League) currentPins() []ScenarioPin {
+	var pins []ScenarioPin
+	for week := l.upcomingWeek(); week <= len(l.Fixtures); week++ {
+		for _, match := range l.weekMatches(week) {
+			if match.isPinned() {
+				pins = append(pins, ScenarioPin{
+					Week:      match.Week,
+					HomeTeam:  match.HomeTeam.Name,
+					AwayTeam:  match.AwayTeam.Name,
+					HomeGoals: match.HomeGoals,
+					AwayGoals: match.AwayGoals,
+				})
+			}
+		}
+	}
+	return pins
+}
+
+// PositionProbabilities runs the rest of the season many times and returns the chance (in %)
+// of each team finishing in each position, first place being the title.
+// scenario can be nil for the league as it stands
+func (l *League) PositionProbabilities(simulations int, scenario *Scenario) map[string][]float64 {
+	positions := make(map[string][]float64)
+	for _, t := range l.Teams {
+		positions[t.Name] = make([]float64, len(l.Teams))
+	}
+	if simulations <= 0 {
+		return positions
+	}
+
+	var pins map[string]ScenarioPin
+	if scenario != nil {
+		pins = scenario.pinMap()
+	}
+
+	r := newSimulationRand()
+	for sim := 0; sim < simulations; sim++ {
+		teamsCopy := l.copyTeams()
+		if scenario != nil {
+			for _, t := range teamsCopy {
+				t.BaseStrength += scenario.Strengths[t.Name]
+			}
+		}
+		l.simulateRemaining(teamsCopy, pins, r)
+
+		sortTeams(teamsCopy)
+		for i, t := range teamsCopy {
+			positions[t.Name][i]++
+		}
+	}
+
+	for name := range positions {
+		for i := range positions[name] {
+			positions[name][i] = positions[name][i] / float64(simulations) * 100.0
+		}
+	}
+	return positions
+}
+
+// ordinal for a league position
+func positionName(position int) string {
+	switch position {
+	case 1:
+		return "1st"
+	case 2:
+		return "2nd"
+	case 3:
+		return "3rd"
+	}
+	return fmt.Sprintf("%dth", position)
+}
+
+// generateScenarioComparison runs each scenario and lays the position probabilities out
+// next to the league as it stands
+func generateScenarioComparison(league *League, scenarios []*Scenario) string {
+	baseline := league.PositionProbabilities(scenarioSimulations, nil)
+	results := make([]map[string][]float64, len(scenarios))
+	for i, scenario := range scenarios {
+		results[i] = league.PositionProbabilities(scenarioSimulations, scenario)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Scenario Comparison (% chance of each position)\n")
+	sb.WriteString(fmt.Sprintf("%-20s %-18s", "Team", "Scenario"))
+	for position := 1; position <= len(league.Teams); position++ {
+		sb.WriteString(fmt.Sprintf(" %6s", positionName(position)))
+	}
+	sb.WriteString("  Title +/-\n")
+	sb.WriteString(strings.Repeat("-", 40+7*len(league.Teams)+11) + "\n")
+
+	for _, team := range league.Teams {
+		sb.WriteString(fmt.Sprintf("%-20s %-18s", team.Name, "Baseline"))
+		for _, p := range baseline[team.Name] {
+			sb.WriteString(fmt.Sprintf(" %6.1f", p))
+		}
+		sb.WriteString("\n")
+
+		for i, scenario := range scenarios {
+			sb.WriteString(fmt.Sprintf("%-20s %-18.18s", "", scenario.Name))
+			for _, p := range results[i][team.Name] {
+				sb.WriteString(fmt.Sprintf(" %6.1f", p))
+			}
+			sb.WriteString(fmt.Sprintf("  %+8.1f\n", results[i][team.Name][0]-baseline[team.Name][0]))
+		}
+	}
+	return sb.String()
+}
+
+// check a strength adjustment typed in by the user
+func validateAdjustment(s string) error {
+	val := 0
+	if n, err := fmt.Sscanf(s, "%d", &val); err != nil || n != 1 {
+		return fmt.Errorf("invalid number")
+	}
+	if val < -maxStrengthAdjustment || val > maxStrengthAdjustment {
+		return fmt.Errorf("adjustment must be between -%d and %d", maxStrengthAdjustment, maxStrengthAdjustment)
+	}
+	return nil
+}
+
+// showScenarios opens the dialog for saving scenarios and comparing them
+func (g *GUI) showScenarios() {
+	var leagueID int64
+	var teamNames []string
+	var pins []ScenarioPin
+	g.engine.Read(func(league *League) {
+		leagueID = league.ID
+		for _, team := range league.Teams {
+			teamNames = append(teamNames, team.Name)
+		}
+		pins = league.currentPins()
+	})
+	if db == nil || leagueID == 0 {
+		return
+	}
+
+	errorLabel := widget.NewLabel("")
+
+	// saving the current pins, plus any strength tweaks, as a new scenario
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Scenario name")
+	adjustEntries := make([]*widget.Entry, len(teamNames))
+	adjustGrid := container.NewGridWithColumns(2)
+	for i, name := range teamNames {
+		adjustEntries[i] = widget.NewEntry()
+		adjustEntries[i].SetText("0")
+		adjustEntries[i].Validator = validateAdjustment
+		adjustGrid.Add(widget.NewLabel(name + " strength +/-"))
+		adjustGrid.Add(adjustEntries[i])
+	}
+
+	// saved scenarios, ticked ones get compared
+	scenarioList := container.NewVBox()
+	selected := make(map[int64]bool)
+	var scenarios []*Scenario
+	var loadScenarios func()
+	loadScenarios = func() {
+		scenarioList.RemoveAll()
+		var err error
+		scenarios, err = db.GetScenarios(leagueID)
+		if err != nil {
+			errorLabel.SetText(fmt.Sprintf("Failed to load scenarios: %v", err))
+			return
+		}
+		if len(scenarios) == 0 {
+			scenarioList.Add(widget.NewLabel("No scenarios saved yet"))
+		}
+		for _, scenario := range scenarios {
+			check := widget.NewCheck(fmt.Sprintf("%s (%d pinned, %d tweaked)", scenario.Name, len(scenario.Pins), len(scenario.Strengths)),
+				func(on bool) {
+					selected[scenario.ID] = on
+				})
+			check.SetChecked(selected[scenario.ID])
+			deleteButton := widget.NewButton("Delete", func() {
+				if err := db.DeleteScenario(scenario.ID); err != nil {
+					errorLabel.SetText(fmt.Sprintf("Failed to delete scenario: %v", err))
+					return
+				}
+				delete(selected, scenario.ID)
+				loadScenarios()
+			})
+			scenarioList.Add(container.NewHBox(check, deleteButton))
+		}
+	}
+	loadScenarios()
+
+	saveButton := widget.NewButton(fmt.Sprintf("Save Current Pins (%d) as Scenario", len(pins)), func() {
+		name := strings.TrimSpace(nameEntry.Text)
+		if name == "" {
+			errorLabel.SetText("Please name the scenario")
+			return
+		}
+		scenario := &Scenario{Name: name, Pins: pins, Strengths: make(map[string]int)}
+		for i, entry := range adjustEntries {
+			if validateAdjustment(entry.Text) != nil {
+				errorLabel.SetText(fmt.Sprintf("Adjustments must be between -%d and %d", maxStrengthAdjustment, maxStrengthAdjustment))
+				return
+			}
+			var adjustment int
+			fmt.Sscanf(entry.Text, "%d", &adjustment)
+			if adjustment != 0 {
+				scenario.Strengths[teamNames[i]] = adjustment
+			}
+		}
+
+		if _, err := db.SaveScenario(leagueID, scenario); err != nil {
+			errorLabel.SetText(fmt.Sprintf("Failed to save scenario: %v", err))
+			return
+		}
+		errorLabel.SetText(fmt.Sprintf("Saved %s", name))
+		nameEntry.SetText("")
+		loadScenarios()
+	})
+
+	content := container.NewVBox(
+		widget.NewLabelWithStyle("Save a Scenario", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		nameEntry,
+		adjustGrid,
+		saveButton,
+		widget.NewLabel(""),
+		widget.NewLabelWithStyle("Saved Scenarios", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		scenarioList,
+		errorLabel,
+	)
+
+	dialog := widget.NewModalPopUp(content, g.window.Canvas())
+
+	var compareButton *widget.Button
+	compareButton = widget.NewButton("Compare", func() {
+		var chosen []*Scenario
+		for _, scenario := range scenarios {
+			if selected[scenario.ID] {
+				chosen = append(chosen, scenario)
+			}
+		}
+		if len(chosen) == 0 {
+			errorLabel.SetText("Tick the scenarios to compare")
+			return
+		}
+
+		// thousands of seasons per scenario, keep it off the ui thread
+		errorLabel.SetText("Running simulations...")
+		compareButton.Disable()
+		go func() {
+			var comparison string
+			g.engine.Snapshot(func(league *League) {
+				comparison = generateScenarioComparison(league, chosen)
+			})
+			fyne.Do(func() {
+				dialog.Hide()
+				g.showScenarioComparison(comparison)
+			})
+		}()
+	})
+
+	buttons := container.NewHBox(
+		compareButton,
+		widget.NewButton("Close", func() {
+			dialog.Hide()
+		}),
+	)
+
+	scroll := container.NewVScroll(content)
+	scroll.SetMinSize(fyne.NewSize(560, 500))
+	dialog.Content = container.NewVBox(scroll, buttons)
+	dialog.Resize(fyne.NewSize(600, 600))
+	dialog.Show()
+}
+
+// show the comparison table from showScenarios
+func (g *GUI) showScenarioComparison(comparison string) {
+	label := widget.NewLabelWithStyle(comparison, fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+	scroll := container.NewScroll(label)
+	scroll.SetMinSize(fyne.NewSize(800, 400))
+
+	dialog := widget.NewModalPopUp(scroll, g.window.Canvas())
+	dialog.Content = container.NewVBox(scroll, widget.NewButton("Close", func() {
+		dialog.Hide()
+	}))
+	dialog.Resize(fyne.NewSize(850, 500))
+	dialog.Show()
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// how a simulated match's score is decided
+const (
+	MatchModelStrength = "strength" // win, draw or loss by relative strength, then a score to fit
+	MatchModelPoisson  = "poisson"  // each side's goals drawn from a poisson distribution around its share of the average
+)
+
+// what a table can be sorted on after points
+const (
+	TieBreakGoalDifference = "goal_difference"
+	TieBreakGoalsFor       = "goals_for"
+	TieBreakGoalsAgainst   = "goals_against" // fewer is better
+	TieBreakWins           = "wins"
+)
+
+// PointsRules are the points a result is worth
+type PointsRules struct {
+	Win          int  `toml:"win" yaml:"win"`
+	Draw         int  `toml:"draw" yaml:"draw"`
+	Loss         int  `toml:"loss" yaml:"loss"`
+	GoalsBonus   int  `toml:"goals_bonus" yaml:"goals_bonus"`       // on top for scoring goals_bonus_at or more, whatever the result
+	GoalsBonusAt int  `toml:"goals_bonus_at" yaml:"goals_bonus_at"` // goals that earn the bonus
+	LosingBonus  int  `toml:"losing_bonus" yaml:"losing_bonus"`     // on top for losing by a single goal
+	Shootouts    bool `toml:"shootouts" yaml:"shootouts"`           // no draws, a level match goes to penalties
+	ShootoutWin  int  `toml:"shootout_win" yaml:"shootout_win"`     // instead of a draw for winning on penalties
+	ShootoutLoss int  `toml:"shootout_loss" yaml:"shootout_loss"`   // instead of a draw for losing on penalties
+}
+
+// points for a result, the team's own goals and penalties first. penalties only
+// count when the league has shootouts, a level match without them is a draw
+func (p PointsRules) forResult(goalsFor, goalsAgainst, penaltiesFor, penaltiesAgainst int) int {
+	var points int
+	switch {
+	case goalsFor > goalsAgainst:
+		points = p.Win
+	case goalsFor < goalsAgainst:
+		points = p.Loss
+		if goalsAgainst-goalsFor == 1 {
+			points += p.LosingBonus
+		}
+	case p.Shootouts && penaltiesFor > penaltiesAgainst:
+		points = p.ShootoutWin
+	case p.Shootouts && penaltiesFor < penaltiesAgainst:
+		points = p.ShootoutLoss
+	default:
+		points = p.Draw
+	}
+	if p.GoalsBonus > 0 && goalsFor >= p.GoalsBonusAt {
+		points += p.GoalsBonus
+	}
+	return points
+}
+
+// outcomes is every pair of points (home, away) a single match can give
+func (p PointsRules) outcomes() [][2]int {
+	// past a goal over the bonus, more goals can't change the points
+	most := 2
+	if p.GoalsBonus > 0 {
+		most = p.GoalsBonusAt + 1
+	}
+	seen := make(map[[2]int]bool)
+	var outcomes [][2]int
+	add := func(outcome [2]int) {
+		if !seen[outcome] {
+			seen[outcome] = true
+			outcomes = append(outcomes, outcome)
+		}
+	}
+	for home := 0; home <= most; home++ {
+		for away := 0; away <= most; away++ {
+			if home == away && p.Shootouts {
+				add([2]int{p.forResult(home, away, 1, 0), p.forResult(away, home, 0, 1)})
+				add([2]int{p.forResult(home, away, 0, 1), p.forResult(away, home, 1, 0)})
+				continue
+			}
+			add([2]int{p.forResult(home, away, 0, 0), p.forResult(away, home, 0, 0)})
+		}
+	}
+	return outcomes
+}
+
+// the most points a single match can give a team
+func (p PointsRules) maxPerMatch() int {
+	most := 0
+	for _, outcome := range p.outcomes() {
+		most = max(most, outcome[0])
+	}
+	return most
+}
+
+// SimulationSettings are the rules and numbers a league runs on. every league keeps its own
+type SimulationSettings struct {
+	MatchModel      string      // MatchModelStrength or MatchModelPoisson
+	DrawChance      float64     // strength model: chance of a draw on top of the home side's win chance
+	MaxWinningGoals int         // strength model: most goals the winner of a simulated match scores
+	AverageGoals    float64     // poisson model: goals in an average match
+	FormWeight      float64     // how far five straight wins (or losses) move strength
+	FormCap         float64     // furthest form can take strength from its base
+	Simulations     int         // seasons simulated for the probability table
+	PlayAllDelayMS  int         // gap between weeks when playing them all at normal speed
+	MaxGoals        int         // most goals a team can be given when editing a result
+	Points          PointsRules // what a win, draw and loss are worth
+	TieBreakers     []string    // what separates teams level on points, in order
+}
+
+// the settings for the league being played. like the league, only touch it with the engine's lock held,
+// and only change it between snapshot jobs, which read it without the lock
+var settings = builtinSettings()
+
+// the numbers the simulator has always used
+func builtinSettings() SimulationSettings {
+	return SimulationSettings{
+		MatchModel:      MatchModelStrength,
+		DrawChance:      0.2,
+		MaxWinningGoals: 3,
+		AverageGoals:    2.7,
+		FormWeight:      0.05,
+		FormCap:         0.15,
+		Simulations:     10000,
+		PlayAllDelayMS:  500,
+		MaxGoals:        9,
+		Points:          PointsRules{Win: 3, Draw: 1, Loss: 0, GoalsBonusAt: 4, ShootoutWin: 2, ShootoutLoss: 1},
+		TieBreakers:     []string{TieBreakGoalDifference},
+	}
+}
+
+// gap between weeks in play all at normal speed
+func (s SimulationSettings) playAllDelay() time.Duration {
+	return time.Duration(s.PlayAllDelayMS) * time.Millisecond
+}
+
+// validate checks every setting is in a range the simulation can work with
+func (s SimulationSettings) validate() error {
+	switch {
+	case s.MatchModel != MatchModelStrength && s.MatchModel != MatchModelPoisson:
+		return fmt.Errorf("match model has to be %s or %s", MatchModelStrength, MatchModelPoisson)
+	case s.DrawChance < 0 || s.DrawChance > 0.5:
+		return fmt.Errorf("draw chance has to be between 0 and 0.5")
+	case s.MaxWinningGoals < 1 || s.MaxWinningGoals > 9:
+		return fmt.Errorf("winning goals has to be between 1 and 9")
+	case s.AverageGoals <= 0 || s.AverageGoals > 10:
+		return fmt.Errorf("average goals has to be above 0 and no more than 10")
+	case s.FormWeight < 0 || s.FormWeight > 0.5:
+		return fmt.Errorf("form weight has to be between 0 and 0.5")
+	case s.FormCap < 0 || s.FormCap > 0.5:
+		return fmt.Errorf("form cap has to be between 0 and 0.5")
+	case s.Simulations < 100 || s.Simulations > 100000:
+		return fmt.Errorf("simulations has to be between 100 and 100000")
+	case s.PlayAllDelayMS < 50 || s.PlayAllDelayMS > 5000:
+		return fmt.Errorf("play all delay has to be between 50 and 5000 ms")
+	case s.MaxGoals < 1 || s.MaxGoals > 99:
+		return fmt.Errorf("maximum goals has to be between 1 and 99")
+	case s.MaxGoals < s.MaxWinningGoals:
+		return fmt.Errorf("maximum goals can't be lower than winning goals, or simulated results couldn't be edited")
+	case s.Points.Win < s.Points.Draw || s.Points.Draw < s.Points.Loss:
+		return fmt.Errorf("a win has to be worth at least a draw, and a draw at least a loss")
+	case s.Points.Loss < 0 || s.Points.Win > 10:
+		return fmt.Errorf("points have to be between 0 and 10")
+	case s.Points.GoalsBonus < 0 || s.Points.GoalsBonus > 5 || s.Points.LosingBonus < 0 || s.Points.LosingBonus > 5:
+		return fmt.Errorf("bonus points have to be between 0 and 5")
+	case s.Points.GoalsBonus > 0 && (s.Points.GoalsBonusAt < 1 || s.Points.GoalsBonusAt > s.MaxGoals):
+		return fmt.Errorf("the goals bonus has to be for between 1 and %d goals", s.MaxGoals)
+	case s.Points.Shootouts && (s.Points.ShootoutWin < s.Points.ShootoutLoss || s.Points.ShootoutLoss < s.Points.Loss || s.Points.ShootoutWin > s.Points.Win):
+		return fmt.Errorf("a shootout win has to be worth no more than a win and at least a shootout loss, and a shootout loss at least a loss")
+	}
+	for _, tieBreaker := range s.TieBreakers {
+		switch tieBreaker {
+		case TieBreakGoalDifference, TieBreakGoalsFor, TieBreakGoalsAgainst, TieBreakWins:
+		default:
+			return fmt.Errorf("unknown tie breaker %q", tieBreaker)
+		}
+	}
+	return nil
+}
+
+// showSettings opens the dialog for changing the current league's settings
+func (g *GUI) showSettings() {
+	current := g.engine.Settings()
+
+	// one entry per setting, set reads the entry back into s
+	type settingField struct {
+		label string
+		hint  string
+		entry *widget.Entry
+		set   func(s *SimulationSettings, text string) error
+	}
+	floatField := func(label, hint string, value float64, target func(s *SimulationSettings) *float64) settingField {
+		entry := widget.NewEntry()
+		entry.SetText(strconv.FormatFloat(value, 'f', -1, 64))
+		return settingField{label, hint, entry, func(s *SimulationSettings, text string) error {
+			v, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+			if err != nil {
+				return fmt.Errorf("%s has to be a number", strings.ToLower(label))
+			}
+			*target(s) = v
+			return nil
+		}}
+	}
+	intField := func(label, hint string, value int, target func(s *SimulationSettings) *int) settingField {
+		entry := widget.NewEntry()
+		entry.SetText(strconv.Itoa(value))
+		return settingField{label, hint, entry, func(s *SimulationSettings, text string) error {
+			v, err := strconv.Atoi(strings.TrimSpace(text))
+			if err != nil {
+				return fmt.Errorf("%s has to be a whole number", strings.ToLower(label))
+			}
+			*target(s) = v
+			return nil
+		}}
+	}
+
+	fields := []settingField{
+		floatField("Draw chance", "on top of the home side's win chance, 0 - 0.5",
+			current.DrawChance, func(s *SimulationSettings) *float64 { return &s.DrawChance }),
+		intField("Winning goals", "the winner scores 1 to this many, 1 - 9",
+			current.MaxWinningGoals, func(s *SimulationSettings) *int { return &s.MaxWinningGoals }),
+		floatField("Form weight", "strength five straight wins add, 0 - 0.5",
+			current.FormWeight, func(s *SimulationSettings) *float64 { return &s.FormWeight }),
+		floatField("Form cap", "furthest form moves strength, 0 - 0.5",
+			current.FormCap, func(s *SimulationSettings) *float64 { return &s.FormCap }),
+		intField("Simulations", "for the probability table, 100 - 100000",
+			current.Simulations, func(s *SimulationSettings) *int { return &s.Simulations }),
+		intField("Play all delay (ms)", "between weeks at normal speed, 50 - 5000",
+			current.PlayAllDelayMS, func(s *SimulationSettings) *int { return &s.PlayAllDelayMS }),
+		intField("Maximum goals", "when editing a result, 1 - 99",
+			current.MaxGoals, func(s *SimulationSettings) *int { return &s.MaxGoals }),
+		intField("Points for a win", "0 - 10",
+			current.Points.Win, func(s *SimulationSettings) *int { return &s.Points.Win }),
+		intField("Points for a draw", "no more than a win",
+			current.Points.Draw, func(s *SimulationSettings) *int { return &s.Points.Draw }),
+		intField("Points for a loss", "no more than a draw",
+			current.Points.Loss, func(s *SimulationSettings) *int { return &s.Points.Loss }),
+		intField("Goals bonus", "on top for scoring enough goals, 0 - 5",
+			current.Points.GoalsBonus, func(s *SimulationSettings) *int { return &s.Points.GoalsBonus }),
+		intField("Goals for the bonus", "goals that earn the goals bonus",
+			current.Points.GoalsBonusAt, func(s *SimulationSettings) *int { return &s.Points.GoalsBonusAt }),
+		intField("Losing bonus", "on top for losing by one goal, 0 - 5",
+			current.Points.LosingBonus, func(s *SimulationSettings) *int { return &s.Points.LosingBonus }),
+		intField("Shootout win", "points for winning on penalties",
+			current.Points.ShootoutWin, func(s *SimulationSettings) *int { return &s.Points.ShootoutWin }),
+		intField("Shootout loss", "points for losing on penalties",
+			current.Points.ShootoutLoss, func(s *SimulationSettings) *int { return &s.Points.ShootoutLoss }),
+	}
+	shootoutsCheck := widget.NewCheck("No draws, level matches go to penalties", nil)
+	shootoutsCheck.SetChecked(current.Points.Shootouts)
+
+	// what's in the entries, checked
+	read := func() (SimulationSettings, error) {
+		s := current
+		for _, f := range fields {
+			if err := f.set(&s, f.entry.Text); err != nil {
+				return s, err
+			}
+		}
+		s.Points.Shootouts = shootoutsCheck.Checked
+		return s, s.validate()
+	}
+	fill := func(s SimulationSettings) {
+		values := []string{
+			strconv.FormatFloat(s.DrawChance, 'f', -1, 64),
+			strconv.Itoa(s.MaxWinningGoals),
+			strconv.FormatFloat(s.FormWeight, 'f', -1, 64),
+			strconv.FormatFloat(s.FormCap, 'f', -1, 64),
+			strconv.Itoa(s.Simulations),
+			strconv.Itoa(s.PlayAllDelayMS),
+			strconv.Itoa(s.MaxGoals),
+			strconv.Itoa(s.Points.Win),
+			strconv.Itoa(s.Points.Draw),
+			strconv.Itoa(s.Points.Loss),
+			strconv.Itoa(s.Points.GoalsBonus),
+			strconv.Itoa(s.Points.GoalsBonusAt),
+			strconv.Itoa(s.Points.LosingBonus),
+			strconv.Itoa(s.Points.ShootoutWin),
+			strconv.Itoa(s.Points.ShootoutLoss),
+		}
+		for i, f := range fields {
+			f.entry.SetText(values[i])
+		}
+		shootoutsCheck.SetChecked(s.Points.Shootouts)
+	}
+
+	grid := container.NewGridWithColumns(3)
+	for _, f := range fields {
+		grid.Add(widget.NewLabel(f.label + ":"))
+		grid.Add(f.entry)
+		grid.Add(widget.NewLabel(f.hint))
+	}
+	statusLabel := widget.NewLabel("")
+
+	content := container.NewVBox(
+		widget.NewLabel("Simulation settings for this league"),
+		grid,
+		shootoutsCheck,
+		statusLabel,
+	)
+	dialog := widget.NewModalPopUp(content, g.window.Canvas())
+
+	buttons := container.NewHBox(
+		widget.NewButton("Apply", func() {
+			s, err := read()
+			if err != nil {
+				statusLabel.SetText(err.Error())
+				return
+			}
+			if err := g.engine.UpdateSettings(s); err != nil {
+				statusLabel.SetText(fmt.Sprintf("Failed to apply: %v", err))
+				return
+			}
+			dialog.Hide()
+		}),
+		widget.NewButton("Save as Default", func() {
+			s, err := read()
+			if err != nil {
+				statusLabel.SetText(err.Error())
+				return
+			}
+			path, err := g.engine.SaveDefaultSettings(s)
+			if err != nil {
+				statusLabel.SetText(fmt.Sprintf("Failed to save: %v", err))
+				return
+			}
+			statusLabel.SetText(fmt.Sprintf("New leagues will start with these, saved to %s", path))
+		}),
+		widget.NewButton("Built in Values", func() {
+			fill(builtinSettings())
+			statusLabel.SetText("")
+		}),
+		widget.NewButton("Cancel", func() {
+			dialog.Hide()
+		}),
+	)
+	dialog.Content = container.NewVBox(content, buttons)
+	dialog.Resize(fyne.NewSize(700, 700))
+	dialog.Show()
+}
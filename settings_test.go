@@ -0,0 +1,68 @@
+package main
+
+import "testing"
+
+func TestForResult(t *testing.T) {
+	standard := builtinSettings().Points
+	bonus := PointsRules{Win: 4, Draw: 2, Loss: 0, GoalsBonus: 1, GoalsBonusAt: 4, LosingBonus: 1}
+	shootouts := PointsRules{Win: 3, Draw: 1, Loss: 0, Shootouts: true, ShootoutWin: 2, ShootoutLoss: 1}
+	twoOne := PointsRules{Win: 2, Draw: 1, Loss: 0}
+
+	tests := []struct {
+		name                           string
+		rules                          PointsRules
+		goalsFor, goalsAgainst         int
+		penaltiesFor, penaltiesAgainst int
+		want                           int
+	}{
+		{"win", standard, 2, 0, 0, 0, 3},
+		{"draw", standard, 1, 1, 0, 0, 1},
+		{"loss", standard, 0, 3, 0, 0, 0},
+		{"penalties ignored without shootouts", standard, 1, 1, 5, 4, 1},
+		{"goals bonus off by default", standard, 5, 0, 0, 0, 3},
+
+		{"bonus win under the mark", bonus, 3, 0, 0, 0, 4},
+		{"bonus win at the mark", bonus, 4, 0, 0, 0, 5},
+		{"bonus draw at the mark", bonus, 4, 4, 0, 0, 3},
+		{"losing bonus by one", bonus, 1, 2, 0, 0, 1},
+		{"no losing bonus by two", bonus, 0, 2, 0, 0, 0},
+		{"losing bonus and goals bonus", bonus, 4, 5, 0, 0, 2},
+
+		{"shootout win", shootouts, 2, 2, 5, 4, 2},
+		{"shootout loss", shootouts, 2, 2, 4, 5, 1},
+		{"shootouts level is a draw", shootouts, 0, 0, 0, 0, 1},
+		{"shootouts still a win in normal time", shootouts, 1, 0, 0, 0, 3},
+
+		{"two points for a win", twoOne, 1, 0, 0, 0, 2},
+		{"one for a draw", twoOne, 0, 0, 0, 0, 1},
+		{"none for a loss", twoOne, 0, 1, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.rules.forResult(tt.goalsFor, tt.goalsAgainst, tt.penaltiesFor, tt.penaltiesAgainst)
+			if got != tt.want {
+				t.Errorf("forResult(%d, %d, %d, %d) = %d, want %d", tt.goalsFor, tt.goalsAgainst, tt.penaltiesFor, tt.penaltiesAgainst, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxPerMatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules PointsRules
+		want  int
+	}{
+		{"standard", builtinSettings().Points, 3},
+		{"goals bonus", PointsRules{Win: 4, Draw: 2, GoalsBonus: 1, GoalsBonusAt: 4}, 5},
+		{"shootouts", PointsRules{Win: 3, Draw: 1, Shootouts: true, ShootoutWin: 2, ShootoutLoss: 1}, 3},
+		{"two points for a win", PointsRules{Win: 2, Draw: 1}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rules.maxPerMatch(); got != tt.want {
+				t.Errorf("maxPerMatch() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
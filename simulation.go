@@ -2,9 +2,12 @@ package main
 
 import (
 	"fmt"
+	"image/color"
+	"math"
 	"math/rand"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -21,6 +24,7 @@ type PremierLeagueTeam struct {
 	BaseStrength int
 	Form         string
 	Position     int
+	Color        color.NRGBA // club colour, for badges
 }
 
 // main team struct that holds all the stats
@@ -37,74 +41,90 @@ type Team struct {
 	BaseStrength    int
 	CurrentStrength int
 	Form            []string // keeping track of last 5 games: "W", "D", "L"
+	Squad           []*Player
+	Availability    float64 // share of full strength left after injuries and bans
+	Adjustment      int     // points the league has taken off or given back, already in Points
 }
 
 // league structure that contains everything
 type League struct {
+	ID       int64 // database id, 0 if it wasn't saved
+	Revision int   // goes up with every change, so cached analysis knows when it's out of date
+	Name     string
+	Season   string
+	Seed     int64 // what the season's random numbers were seeded with, 0 if it was picked up from the database
 	Teams    []*Team
 	Week     int
 	Fixtures [][]Match
+	Rounds   []Round        // when each week is played, empty for a league saved before there were dates
+	Calendar CalendarConfig // the calendar the dates came from, for rearranging matches into them
+
+	Adjustments        []PointsAdjustment  // points deductions and additions, oldest week first
+	ProbabilityHistory []WeekProbabilities // title chances and expected positions after each week
+	PositionHistory    []WeekStandings     // the table after each week, rebuilt with the stats
 }
 
 // single match with all the details
 type Match struct {
-	HomeTeam  *Team
-	AwayTeam  *Team
-	HomeGoals int
-	AwayGoals int
-	IsPlayed  bool
-	IsFixed   bool // whether user manually changed the result
-	Week      int  // which week this match belongs to
+	HomeTeam      *Team
+	AwayTeam      *Team
+	HomeGoals     int
+	AwayGoals     int
+	HomePenalties int // a level match's shootout, both 0 if there wasn't one
+	AwayPenalties int
+	IsPlayed      bool
+	IsFixed       bool         // whether user manually changed the result, or pinned it before it was played
+	Week          int          // which week this match belongs to
+	Status        string       // MatchPostponed, MatchAbandoned or MatchWalkover, "" if it's going ahead as scheduled
+	PlayWeek      int          // the week a rearranged match is now played in, 0 for its own week
+	Midweek       bool         // rearranged into the midweek before PlayWeek's round
+	Kickoff       time.Time    // zero for a league saved before there were dates
+	Events        []MatchEvent // the match timeline from the match engine
+	Goals         []Goal
+	Cards         []Card
+	Injuries      []Injury
 }
 
 // mock premier league teams with realistic strengths
 func getMockPremierLeagueTeams() []PremierLeagueTeam {
 	return []PremierLeagueTeam{
-		{ID: 1, Name: "Manchester City", ShortName: "MCI", BaseStrength: 85, Form: "", Position: 1},
-		{ID: 2, Name: "Arsenal", ShortName: "ARS", BaseStrength: 82, Form: "", Position: 2},
-		{ID: 3, Name: "Liverpool", ShortName: "LIV", BaseStrength: 83, Form: "", Position: 3},
-		{ID: 4, Name: "Manchester United", ShortName: "MUN", BaseStrength: 80, Form: "", Position: 4},
-		{ID: 5, Name: "Tottenham", ShortName: "TOT", BaseStrength: 79, Form: "", Position: 5},
-		{ID: 6, Name: "Newcastle", ShortName: "NEW", BaseStrength: 78, Form: "", Position: 6},
-		{ID: 7, Name: "Chelsea", ShortName: "CHE", BaseStrength: 77, Form: "", Position: 7},
-		{ID: 8, Name: "Aston Villa", ShortName: "AVL", BaseStrength: 76, Form: "", Position: 8},
-		{ID: 9, Name: "Brighton", ShortName: "BHA", BaseStrength: 75, Form: "", Position: 9},
-		{ID: 10, Name: "West Ham", ShortName: "WHU", BaseStrength: 74, Form: "", Position: 10},
+		{ID: 1, Name: "Manchester City", ShortName: "MCI", BaseStrength: 85, Form: "", Position: 1, Color: color.NRGBA{108, 171, 221, 255}},
+		{ID: 2, Name: "Arsenal", ShortName: "ARS", BaseStrength: 82, Form: "", Position: 2, Color: color.NRGBA{239, 1, 7, 255}},
+		{ID: 3, Name: "Liverpool", ShortName: "LIV", BaseStrength: 83, Form: "", Position: 3, Color: color.NRGBA{200, 16, 46, 255}},
+		{ID: 4, Name: "Manchester United", ShortName: "MUN", BaseStrength: 80, Form: "", Position: 4, Color: color.NRGBA{218, 41, 28, 255}},
+		{ID: 5, Name: "Tottenham", ShortName: "TOT", BaseStrength: 79, Form: "", Position: 5, Color: color.NRGBA{19, 34, 87, 255}},
+		{ID: 6, Name: "Newcastle", ShortName: "NEW", BaseStrength: 78, Form: "", Position: 6, Color: color.NRGBA{36, 31, 32, 255}},
+		{ID: 7, Name: "Chelsea", ShortName: "CHE", BaseStrength: 77, Form: "", Position: 7, Color: color.NRGBA{3, 70, 148, 255}},
+		{ID: 8, Name: "Aston Villa", ShortName: "AVL", BaseStrength: 76, Form: "", Position: 8, Color: color.NRGBA{103, 14, 54, 255}},
+		{ID: 9, Name: "Brighton", ShortName: "BHA", BaseStrength: 75, Form: "", Position: 9, Color: color.NRGBA{0, 87, 184, 255}},
+		{ID: 10, Name: "West Ham", ShortName: "WHU", BaseStrength: 74, Form: "", Position: 10, Color: color.NRGBA{122, 38, 58, 255}},
 	}
 }
 
-// randomly pick 4 teams from the list
-func selectRandomTeams(teams []PremierLeagueTeam) []PremierLeagueTeam {
-	rand.Seed(time.Now().UnixNano())
-	rand.Shuffle(len(teams), func(i, j int) {
-		teams[i], teams[j] = teams[j], teams[i]
-	})
+// random numbers for playing the season, seeded from the league config so a seed
+// gives the same season. like settings, only used with the engine's lock held
+var rng = rand.New(rand.NewSource(time.Now().UnixNano()))
 
-	if len(teams) < 4 {
-		return teams
-	}
-	return teams[:4]
+// random numbers for a batch of simulated seasons. they're kept apart from the season's
+// own so working out probabilities doesn't change what happens, and so batches can run side by side
+func newSimulationRand() *rand.Rand {
+	return rand.New(rand.NewSource(rand.Int63()))
 }
 
-// create a new league with 4 random teams
-func NewLeague() *League {
-	premierLeagueTeams := getMockPremierLeagueTeams()
-	selectedTeams := selectRandomTeams(premierLeagueTeams)
+// create a new league with teams picked from the config
+func NewLeague(config LeagueConfig, r *rand.Rand) *League {
+	selectedTeams := config.pickTeams(r)
 
-	leagueTeams := make([]*Team, 4)
+	leagueTeams := make([]*Team, len(selectedTeams))
 	for i, team := range selectedTeams {
-		// convert form string to array if needed
-		form := make([]string, 5)
-		for j, result := range team.Form {
-			form[j] = string(result)
-		}
-
 		leagueTeams[i] = &Team{
 			Name:            team.Name,
-			BaseStrength:    team.BaseStrength,
-			CurrentStrength: team.BaseStrength,
-			Form:            form,
+			BaseStrength:    team.Strength,
+			CurrentStrength: team.Strength,
+			Form:            make([]string, 5),
+			Availability:    1.0,
 		}
+		leagueTeams[i].Squad = generateSquad(leagueTeams[i])
 	}
 
 	return &League{
@@ -124,41 +144,44 @@ func (t *Team) updateTeamStrength() {
 		weight := float64(5-i) / 15.0 // recent games matter more
 		switch result {
 		case "W":
-			formMultiplier += 0.05 * weight // wins boost strength
+			formMultiplier += settings.FormWeight * weight // wins boost strength
 		case "D":
 			// draws don't change anything
 		case "L":
-			formMultiplier -= 0.05 * weight // losses hurt strength
+			formMultiplier -= settings.FormWeight * weight // losses hurt strength
 		}
 	}
 
 	// apply the form modifier
 	t.CurrentStrength = int(float64(t.BaseStrength) * formMultiplier)
 
-	// don't let it go too crazy - cap at ±15%
-	minStrength := int(float64(t.BaseStrength) * 0.85)
-	maxStrength := int(float64(t.BaseStrength) * 1.15)
+	// don't let it go too crazy - cap it, ±15% unless the settings say otherwise
+	minStrength := int(float64(t.BaseStrength) * (1 - settings.FormCap))
+	maxStrength := int(float64(t.BaseStrength) * (1 + settings.FormCap))
 
 	if t.CurrentStrength < minStrength {
 		t.CurrentStrength = minStrength
 	} else if t.CurrentStrength > maxStrength {
 		t.CurrentStrength = maxStrength
 	}
+
+	// missing players take it down further
+	t.CurrentStrength = int(float64(t.CurrentStrength) * t.Availability)
 }
 
-// UpdateTeamStats updates a team's statistics after a match
-func (t *Team) UpdateTeamStats(goalsFor, goalsAgainst int) {
+// UpdateTeamStats updates a team's statistics after a match. a match settled on
+// penalties still goes down as drawn, only the points tell who won the shootout
+func (t *Team) UpdateTeamStats(goalsFor, goalsAgainst, penaltiesFor, penaltiesAgainst int) {
 	t.GoalsFor += goalsFor
 	t.GoalsAgainst += goalsAgainst
 	t.GoalDifference = t.GoalsFor - t.GoalsAgainst
+	t.Points += settings.Points.forResult(goalsFor, goalsAgainst, penaltiesFor, penaltiesAgainst)
 
 	if goalsFor > goalsAgainst {
 		t.Won++
-		t.Points += 3
 		t.Form = append([]string{"W"}, t.Form[:4]...)
 	} else if goalsFor == goalsAgainst {
 		t.Drawn++
-		t.Points += 1
 		t.Form = append([]string{"D"}, t.Form[:4]...)
 	} else {
 		t.Lost++
@@ -169,17 +192,16 @@ func (t *Team) UpdateTeamStats(goalsFor, goalsAgainst int) {
 }
 
 // reverse team stats when undoing a match result
-func (t *Team) ReverseTeamStats(goalsFor, goalsAgainst int) {
+func (t *Team) ReverseTeamStats(goalsFor, goalsAgainst, penaltiesFor, penaltiesAgainst int) {
 	t.GoalsFor -= goalsFor
 	t.GoalsAgainst -= goalsAgainst
 	t.GoalDifference = t.GoalsFor - t.GoalsAgainst
+	t.Points -= settings.Points.forResult(goalsFor, goalsAgainst, penaltiesFor, penaltiesAgainst)
 
 	if goalsFor > goalsAgainst {
 		t.Won--
-		t.Points -= 3
 	} else if goalsFor == goalsAgainst {
 		t.Drawn--
-		t.Points -= 1
 	} else {
 		t.Lost--
 	}
@@ -187,7 +209,12 @@ func (t *Team) ReverseTeamStats(goalsFor, goalsAgainst int) {
 }
 
 // predict match result based on team strengths - this is where the magic happens
-func predictMatchResult(team1, team2 *Team) (int, int) {
+func predictMatchResult(team1, team2 *Team, r *rand.Rand) (int, int) {
+	if settings.MatchModel == MatchModelPoisson {
+		expected1, expected2 := expectedGoals(team1, team2)
+		return poissonGoals(r, expected1), poissonGoals(r, expected2)
+	}
+
 	// figure out total strength
 	totalStrength := team1.CurrentStrength + team2.CurrentStrength
 
@@ -195,35 +222,61 @@ func predictMatchResult(team1, team2 *Team) (int, int) {
 	team1Prob := float64(team1.CurrentStrength) / float64(totalStrength)
 
 	// roll the dice
-	rand.Seed(time.Now().UnixNano())
-	r := rand.Float64()
+	roll := r.Float64()
 
 	// decide the score based on probability
 	var team1Goals, team2Goals int
 
-	if r < team1Prob {
+	if roll < team1Prob {
 		// team 1 wins
-		team1Goals = rand.Intn(3) + 1
-		team2Goals = rand.Intn(team1Goals)
-	} else if r < team1Prob+0.2 {
+		team1Goals = r.Intn(settings.MaxWinningGoals) + 1
+		team2Goals = r.Intn(team1Goals)
+	} else if roll < team1Prob+settings.DrawChance {
 		// it's a draw
-		team1Goals = rand.Intn(2)
+		team1Goals = r.Intn(2)
 		team2Goals = team1Goals
 	} else {
 		// team 2 wins
-		team2Goals = rand.Intn(3) + 1
-		team1Goals = rand.Intn(team2Goals)
+		team2Goals = r.Intn(settings.MaxWinningGoals) + 1
+		team1Goals = r.Intn(team2Goals)
 	}
 
 	return team1Goals, team2Goals
 }
 
-// generate fixtures for 18 weeks with 4 teams
-func (l *League) generateFixtures() [][]Match {
+// goals each side can expect in the poisson model, the average split by strength
+func expectedGoals(team1, team2 *Team) (float64, float64) {
+	share := float64(team1.CurrentStrength) / float64(team1.CurrentStrength+team2.CurrentStrength)
+	return settings.AverageGoals * share, settings.AverageGoals * (1 - share)
+}
+
+// chance of scoring exactly goals when expecting expected of them
+func poissonChance(expected float64, goals int) float64 {
+	p := math.Exp(-expected)
+	for i := 1; i <= goals; i++ {
+		p *= expected / float64(i)
+	}
+	return p
+}
+
+// goals drawn from a poisson distribution, no more than a result can be edited to
+func poissonGoals(r *rand.Rand, expected float64) int {
+	goals := 0
+	roll := r.Float64()
+	for p := poissonChance(expected, 0); roll > p && goals < settings.MaxGoals; p = poissonChance(expected, goals) {
+		roll -= p
+		goals++
+	}
+	return goals
+}
+
+// generate the fixtures: every team plays every other rounds times, home and away in turn.
+// with an odd number of teams somebody sits each week out
+func (l *League) generateFixtures(rounds int) [][]Match {
 	teams := l.Teams
 	numTeams := len(teams)
-	if numTeams != 4 {
-		panic("This fixture generator is designed for exactly 4 teams.")
+	if numTeams%2 == 1 {
+		numTeams++ // the extra slot is the week off
 	}
 
 	// set up team indices for round-robin
@@ -234,47 +287,78 @@ func (l *League) generateFixtures() [][]Match {
 
 	var allWeeks [][]Match
 
-	// repeat the double round-robin 3 times to get 18 weeks
-	for repeat := 0; repeat < 3; repeat++ {
-		// first double round-robin (home/away)
-		for half := 0; half < 2; half++ {
-			// reset indices for each double round-robin
-			idx := make([]int, numTeams)
-			copy(idx, indices)
-			for round := 0; round < numTeams-1; round++ {
-				var week []Match
-				for i := 0; i < numTeams/2; i++ {
-					home := idx[i]
-					away := idx[numTeams-1-i]
-					if half == 1 {
-						home, away = away, home // swap home/away for second half
-					}
-					week = append(week, Match{
-						HomeTeam: teams[home],
-						AwayTeam: teams[away],
-					})
+	for round := 0; round < rounds; round++ {
+		// every other round swaps home and away
+		swap := round%2 == 1
+
+		// reset indices for each round
+		idx := make([]int, numTeams)
+		copy(idx, indices)
+		for w := 0; w < numTeams-1; w++ {
+			var week []Match
+			for i := 0; i < numTeams/2; i++ {
+				home := idx[i]
+				away := idx[numTeams-1-i]
+				if home >= len(teams) || away >= len(teams) {
+					continue // that one's week off
 				}
-				allWeeks = append(allWeeks, week)
-				// rotate indices for next round
-				tmp := idx[1]
-				copy(idx[1:numTeams-1], idx[2:])
-				idx[numTeams-1] = tmp
+				if swap {
+					home, away = away, home
+				}
+				week = append(week, Match{
+					HomeTeam: teams[home],
+					AwayTeam: teams[away],
+				})
 			}
+			allWeeks = append(allWeeks, week)
+			// rotate indices for next week
+			tmp := idx[1]
+			copy(idx[1:numTeams-1], idx[2:])
+			idx[numTeams-1] = tmp
 		}
 	}
 
 	return allWeeks
 }
 
-// SimulateNextWeek simulates the next week of matches
+// generate the fixtures and number the weeks so what's shown as upcoming is what gets played
+func (l *League) scheduleFixtures(rounds int) {
+	l.Fixtures = l.generateFixtures(rounds)
+	for week := range l.Fixtures {
+		for i := range l.Fixtures[week] {
+			l.Fixtures[week][i].Week = week + 1
+		}
+	}
+}
+
+// play every match in week that hasn't been decided, a pinned score is played out as it
+// was set. returns the week's matches
+func (l *League) playWeek(week int) []*Match {
+	matches := l.weekMatches(week)
+	for _, match := range matches {
+		if match.isPinned() {
+			// the score was set in advance, play the rest of the match around it
+			match.playTimeline(match.HomeGoals, match.AwayGoals)
+			match.IsPlayed = true
+		} else if !match.IsFixed {
+			match.playMatch()
+		}
+	}
+	return matches
+}
+
+// SimulateNextWeek plays the next week straight on the league and prints the results.
+// nothing goes into the history or the database
+//
+// Deprecated: use SeasonEngine.SimulateWeek, which plays the week the same way and keeps
+// the history and the database up to date.
 func (l *League) SimulateNextWeek() bool {
 	if l.Week == 0 {
 		l.Week = 1
-	}
-
-	// generate fixtures if this is the first week
-	if l.Week == 1 {
-		l.Fixtures = l.generateFixtures()
+		// fixtures are scheduled up front, only generate them if they're missing
+		if l.Fixtures == nil {
+			l.scheduleFixtures(leagueConfig.Rounds)
+		}
 	}
 
 	// check if we've played all weeks already
@@ -285,28 +369,19 @@ func (l *League) SimulateNextWeek() bool {
 	// play this week's matches
 	fmt.Printf("\nWeek %d Results:\n", l.Week)
 	fmt.Println("----------------")
-
-	for _, match := range l.Fixtures[l.Week-1] {
-		homeGoals, awayGoals := predictMatchResult(match.HomeTeam, match.AwayTeam)
-		fmt.Printf("%s %d - %d %s\n", match.HomeTeam.Name, homeGoals, awayGoals, match.AwayTeam.Name)
-
-		match.HomeTeam.UpdateTeamStats(homeGoals, awayGoals)
-		match.AwayTeam.UpdateTeamStats(awayGoals, homeGoals)
+	for _, match := range l.playWeek(l.Week) {
+		fmt.Printf("%s %d - %d %s%s\n", match.HomeTeam.Name, match.HomeGoals, match.AwayGoals, match.AwayTeam.Name, match.penaltiesNote())
 	}
 
 	l.Week++
+	l.RecalculateStats()
 	return true
 }
 
 // print the league table nicely formatted
 func (l *League) PrintLeagueTable() {
-	// sort teams by points then goal difference
-	sort.Slice(l.Teams, func(i, j int) bool {
-		if l.Teams[i].Points != l.Teams[j].Points {
-			return l.Teams[i].Points > l.Teams[j].Points
-		}
-		return l.Teams[i].GoalDifference > l.Teams[j].GoalDifference
-	})
+	// sort teams by points then the tie breakers
+	l.sortStandings()
 
 	// print the header
 	fmt.Printf("\n%-20s %-8s %-8s %-8s %-8s %-8s %-8s %-8s %-8s %-8s\n",
@@ -316,21 +391,46 @@ func (l *League) PrintLeagueTable() {
 	// print each team's stats
 	for _, team := range l.Teams {
 		fmt.Printf("%-20s %-8d %-8d %-8d %-8d %-8d %-8d %-8d %-8d %-8d\n",
-			team.Name, team.Played, team.Won, team.Drawn, team.Lost,
+			team.annotatedName(), team.Played, team.Won, team.Drawn, team.Lost,
 			team.GoalsFor, team.GoalsAgainst, team.GoalDifference, team.Points, team.CurrentStrength)
 	}
+	fmt.Print(generateAdjustmentNotes(l))
 }
 
 // gui structure to handle the interface
 type GUI struct {
 	window         fyne.Window
-	league         *League
-	tableLabel     *widget.Label
+	engine         *SeasonEngine
 	weekLabel      *widget.Label
-	weekResults    *widget.Label // for week results
-	allResults     *widget.Label // for season overview
-	currentWeek    int           // which week we're currently viewing
-	showAllResults bool          // whether to show the full season results
+	currentWeek    int         // which week we're currently viewing
+	showAllResults bool        // whether to show the full season results
+	liveMode       atomic.Bool // whether matches play out in accelerated real time
+	livePlaying    atomic.Bool // a live week is on screen, hold off refreshing until it's over
+	autoPlay       *autoPlay   // play all / simulate to week
+	undoText       string      // what undo and redo would do, "" if nothing
+	redoText       string
+	leverage       map[string]FixtureLeverage // upcoming fixtures by fixtureKey, nil until worked out
+	leverageKey    string                     // which league and revision leverage is for
+	titleChances   map[string]float64         // % chance of the title by team, nil until worked out
+	titleKey       string                     // which league and revision the title chances are for
+	titleBusy      bool                       // title chances are being worked out
+
+	// the main view is built once and updated in place by render
+	content          *fyne.Container
+	standings        *sortableTable
+	probabilities    *sortableTable
+	allResults       *sortableTable
+	resultMatches    map[string]Match // matches in allResults by fixtureKey
+	predictorLabel   *widget.Label
+	adjustmentsLabel *widget.Label
+	weekResults      *fyne.Container
+	upcomingLabel    *widget.Label
+	teamNewsLabel    *widget.Label
+	scorersLabel     *widget.Label
+	weekView         *fyne.Container
+	allResultsView   *fyne.Container
+	controls         *fyne.Container
+	windowSize       fyne.Size // last size render asked for
 }
 
 // create a new gui instance
@@ -338,19 +438,11 @@ func NewGUI() *GUI {
 	myApp := app.New()
 	window := myApp.NewWindow("Premier League Simulator")
 
-	league := NewLeague()
-	// set up fixtures right away
-	league.Fixtures = league.generateFixtures()
-	league.Week = 0
-
 	gui := &GUI{
-		window:         window,
-		league:         league,
-		tableLabel:     widget.NewLabel(""),
-		weekLabel:      widget.NewLabel("Week 0"),
-		weekResults:    widget.NewLabel(""),
-		allResults:     widget.NewLabel(""),
-		showAllResults: false,
+		window:    window,
+		engine:    NewSeasonEngine(),
+		weekLabel: widget.NewLabel("Week 0"),
+		autoPlay:  &autoPlay{speed: 1},
 	}
 
 	gui.setupUI()
@@ -359,202 +451,206 @@ func NewGUI() *GUI {
 
 // setupUI sets up the user interface
 func (g *GUI) setupUI() {
-	// set up standings with nice formatting
-	standings := "Team                 P    W    D    L    GF   GA   GD   PTS\n"
-	standings += "--------------------------------------------------------\n"
-	for _, team := range g.league.Teams {
-		standings += fmt.Sprintf("%-20s %3d  %3d  %3d  %3d  %3d  %3d  %3d  %3d\n",
-			team.Name,
-			team.Played,
-			team.Won,
-			team.Drawn,
-			team.Lost,
-			team.GoalsFor,
-			team.GoalsAgainst,
-			team.GoalDifference,
-			team.Points)
-	}
+	g.buildMainView()
 
-	// calculate starting probabilities based on base strength
-	totalStrength := 0
-	for _, team := range g.league.Teams {
-		totalStrength += team.BaseStrength
-	}
-	initialProbs := make(map[string]float64)
-	for _, team := range g.league.Teams {
-		initialProbs[team.Name] = float64(team.BaseStrength) / float64(totalStrength) * 100.0
-	}
+	// redraw whenever the engine changes the league, always on the ui thread
+	g.engine.Subscribe(func() {
+		fyne.Do(func() {
+			if g.livePlaying.Load() {
+				return
+			}
+			g.refreshDisplay()
+		})
+	})
+	g.addUndoShortcuts()
 
-	// sort teams by their probability
-	type teamProb struct {
-		name string
-		prob float64
+	g.refreshDisplay()
+}
+
+// simulateNextWeek simulates the next week of matches
+func (g *GUI) simulateNextWeek() {
+	live := g.liveMode.Load()
+	g.livePlaying.Store(live)
+
+	week, ok := g.engine.SimulateWeek()
+	if !ok {
+		// the season is already over
+		g.livePlaying.Store(false)
+		g.refreshDisplay()
+		return
 	}
-	var probList []teamProb
-	for name, prob := range initialProbs {
-		probList = append(probList, teamProb{name, prob})
+
+	// in live mode the table only updates once the matches have played out
+	if live {
+		g.playWeekLive(week, g.finishLiveWeek)
 	}
-	sort.Slice(probList, func(i, j int) bool {
-		return probList[i].prob > probList[j].prob
-	})
+}
 
-	// make the probability table with same width as standings
-	probTable := "Championship Probability\n"
-	probTable += "----------------------\n"
-	for _, tp := range probList {
-		probTable += fmt.Sprintf("%-20s %6.2f%%\n", tp.name, tp.prob)
+// back to the normal view once a live week has played out
+func (g *GUI) finishLiveWeek() {
+	g.livePlaying.Store(false)
+	g.refreshDisplay()
+}
+
+// buildMainView creates the widgets of the main view, render fills them in
+func (g *GUI) buildMainView() {
+	g.standings = newSortableTable([]tableColumn{
+		{"Pos", 55}, {"Team", 190}, {"P", 52}, {"GH", 52}, {"W", 52}, {"D", 52}, {"L", 52},
+		{"GF", 52}, {"GA", 52}, {"GD", 52}, {"Pts", 55}, {"Form", 80},
+	}, 20)
+	g.probabilities = newSortableTable([]tableColumn{{"Team", 190}, {"Title", 80}}, 20)
+
+	// picking a team opens its page
+	showTeam := func(table *sortableTable) func(tableRow) {
+		return func(row tableRow) {
+			table.ClearSelection()
+			g.showTeamDetail(row.Key)
+		}
+	}
+	g.standings.OnSelected = showTeam(g.standings)
+	g.probabilities.OnSelected = showTeam(g.probabilities)
+
+	// picking a result opens its match report
+	g.allResults = newSortableTable([]tableColumn{
+		{"Week", 65}, {"Kickoff", 140}, {"Home", 190}, {"Score", 70}, {"Away", 190}, {"", 150},
+	}, 12)
+	g.allResults.OnSelected = func(row tableRow) {
+		g.allResults.ClearSelection()
+		if match, ok := g.resultMatches[row.Key]; ok {
+			g.showMatchReport(match)
+		}
 	}
 
-	standingsLabel := widget.NewLabelWithStyle(standings, fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
-	probLabel := widget.NewLabelWithStyle(probTable, fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
-	upcomingMatchesLabel := widget.NewLabelWithStyle(g.generateUpcomingMatchesTable(), fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+	mono := fyne.TextStyle{Monospace: true}
+	g.predictorLabel = widget.NewLabelWithStyle("", fyne.TextAlignLeading, mono)
+	g.adjustmentsLabel = widget.NewLabelWithStyle("", fyne.TextAlignLeading, mono)
+	g.upcomingLabel = widget.NewLabelWithStyle("", fyne.TextAlignLeading, mono)
+	g.teamNewsLabel = widget.NewLabelWithStyle("", fyne.TextAlignLeading, mono)
+	g.scorersLabel = widget.NewLabelWithStyle("", fyne.TextAlignLeading, mono)
+	g.weekResults = container.NewVBox()
+	g.controls = container.NewVBox()
 
-	// layout with proper spacing
 	topRow := container.NewHBox(
-		standingsLabel,
-		widget.NewLabel("     "), // spacer to separate tables
-		probLabel,
+		container.NewVBox(g.standings.holder, g.adjustmentsLabel),
+		widget.NewLabel("  "),
+		g.probabilities.holder,
+		widget.NewLabel("  "),
+		g.predictorLabel,
 	)
-	mainContent := container.NewVBox(
-		topRow,
-		widget.NewLabel(""), // vertical spacer
-		upcomingMatchesLabel,
+	g.weekView = container.NewVBox(
+		g.weekResults,
+		widget.NewLabel(""),
+		g.upcomingLabel,
+		g.teamNewsLabel,
+		g.scorersLabel,
 	)
-
-	// button layout at the bottom
-	simulateButton := widget.NewButton("Simulate Next Week", g.simulateNextWeek)
-	playAllButton := widget.NewButton("Play All Remaining Weeks", g.simulateAllRemainingWeeks)
-	buttonRow := container.NewHBox(
-		simulateButton,
-		widget.NewLabel("  "), // spacer between buttons
-		playAllButton,
+	g.allResultsView = container.NewVBox(
+		widget.NewLabel("All Season Results"),
+		g.allResults.holder,
 	)
 
-	g.tableLabel.SetText("")
-	g.window.SetContent(container.NewVBox(
+	g.content = container.NewVBox(
 		g.weekLabel,
-		mainContent,
+		topRow,
 		widget.NewLabel(""),
-		buttonRow,
-	))
-
-	g.window.Resize(fyne.NewSize(900, 700))
+		g.weekView,
+		g.allResultsView,
+		widget.NewLabel(""),
+		g.controls,
+	)
 }
 
-// simulateNextWeek simulates the next week of matches
-func (g *GUI) simulateNextWeek() {
-	if g.league.Week == 0 {
-		g.league.Week = 1
-		g.league.Fixtures = g.league.generateFixtures()
-		// set week numbers for all matches
-		for week := range g.league.Fixtures {
-			for i := range g.league.Fixtures[week] {
-				g.league.Fixtures[week][i].Week = week + 1
-			}
-		}
-	}
-
-	// check if we've reached the end of the season
-	if g.league.Week > 18 {
-		g.weekLabel.SetText("Season Completed!")
-		g.weekResults.SetText("")
-		g.refreshDisplay()
-		return
-	}
-
-	// play the current week's matches
-	weekMatches := g.league.Fixtures[g.league.Week-1]
-	for i := range weekMatches {
-		match := &g.league.Fixtures[g.league.Week-1][i]
-		if !match.IsFixed {
-			homeGoals, awayGoals := predictMatchResult(match.HomeTeam, match.AwayTeam)
-			match.HomeGoals = homeGoals
-			match.AwayGoals = awayGoals
-			match.IsPlayed = true
+// the league table, one row per team
+func standingsRows(league *League) []tableRow {
+	var rows []tableRow
+	inHand := league.gamesInHand()
+	for i, team := range league.Teams {
+		gamesInHand := ""
+		if inHand[team.Name] > 0 {
+			gamesInHand = fmt.Sprintf("%d", inHand[team.Name])
 		}
+		rows = append(rows, tableRow{
+			Key: team.Name,
+			Cells: []string{
+				fmt.Sprintf("%d", i+1),
+				team.annotatedName(),
+				fmt.Sprintf("%d", team.Played),
+				gamesInHand,
+				fmt.Sprintf("%d", team.Won),
+				fmt.Sprintf("%d", team.Drawn),
+				fmt.Sprintf("%d", team.Lost),
+				fmt.Sprintf("%d", team.GoalsFor),
+				fmt.Sprintf("%d", team.GoalsAgainst),
+				fmt.Sprintf("%d", team.GoalDifference),
+				fmt.Sprintf("%d", team.Points),
+				strings.Join(team.Form, ""),
+			},
+		})
 	}
-
-	g.currentWeek = g.league.Week
-	g.weekLabel.SetText(fmt.Sprintf("Week %d", g.league.Week))
-	g.league.Week++
-
-	// recalculate all the stats
-	g.recalculateAllStats()
-
-	// refresh the display
-	g.refreshDisplay()
+	return rows
 }
 
-// recalculate all team stats from scratch
-func (g *GUI) recalculateAllStats() {
-	// reset all team stats first
-	for _, team := range g.league.Teams {
-		team.ResetTeamStats()
+// everyone's chance of the title, favourites first. teams the chances haven't been
+// worked out for yet go at the bottom
+func probabilityRows(league *League, probs map[string]float64) []tableRow {
+	var names []string
+	for _, team := range league.Teams {
+		names = append(names, team.Name)
 	}
-
-	// go through all matches week by week
-	for week := 0; week < g.league.Week; week++ {
-		if week >= len(g.league.Fixtures) {
-			break
+	sort.SliceStable(names, func(i, j int) bool {
+		pi, iok := probs[names[i]]
+		pj, jok := probs[names[j]]
+		if iok != jok {
+			return iok
 		}
-		for i := range g.league.Fixtures[week] {
-			match := &g.league.Fixtures[week][i]
-			if match.IsPlayed || match.IsFixed {
-				match.HomeTeam.UpdateTeamStats(match.HomeGoals, match.AwayGoals)
-				match.AwayTeam.UpdateTeamStats(match.AwayGoals, match.HomeGoals)
-			}
+		return pi > pj
+	})
+
+	var rows []tableRow
+	for _, name := range names {
+		chance := "..."
+		if p, ok := probs[name]; ok {
+			chance = fmt.Sprintf("%.2f%%", p)
 		}
+		rows = append(rows, tableRow{Key: name, Cells: []string{name, chance}})
 	}
+	return rows
 }
 
-// helper functions to make the display tables
-func (g *GUI) generateStandingsTable() string {
-	standings := "Team                 P    W    D    L    GF   GA   GD   PTS\n"
-	standings += "--------------------------------------------------------\n"
-	for _, team := range g.league.Teams {
-		standings += fmt.Sprintf("%-20s %3d  %3d  %3d  %3d  %3d  %3d  %3d  %3d\n",
-			team.Name,
-			team.Played,
-			team.Won,
-			team.Drawn,
-			team.Lost,
-			team.GoalsFor,
-			team.GoalsAgainst,
-			team.GoalDifference,
-			team.Points)
+// start working out the title chances if the league has changed since they were last
+// worked out. one lot at a time, when it's done the next render starts another if the
+// league has moved on meanwhile. until then the last chances stay up
+func (g *GUI) checkTitleChances(league *League) {
+	key := fmt.Sprintf("%d:%d", league.ID, league.Revision)
+	if g.titleKey == key || g.titleBusy {
+		return
 	}
-	return standings
+	g.titleBusy = true
+	go g.updateTitleChances()
 }
 
-func (g *GUI) generateProbabilityTable() string {
-	probs := g.league.ChampionshipProbabilities(10000)
-
-	type teamProb struct {
-		name string
-		prob float64
-	}
-	var probList []teamProb
-	for name, prob := range probs {
-		probList = append(probList, teamProb{name, prob})
-	}
-	sort.Slice(probList, func(i, j int) bool {
-		return probList[i].prob > probList[j].prob
+// work out the title chances on a snapshot of the league and redraw when they're ready
+func (g *GUI) updateTitleChances() {
+	var key string
+	var chances map[string]float64
+	g.engine.Snapshot(func(league *League) {
+		key = fmt.Sprintf("%d:%d", league.ID, league.Revision)
+		chances = league.ChampionshipProbabilities(settings.Simulations)
 	})
 
-	probTable := "Championship Probability\n"
-	probTable += "----------------------\n"
-	for _, tp := range probList {
-		probTable += fmt.Sprintf("%-20s %6.2f%%\n", tp.name, tp.prob)
-	}
-	return probTable
+	fyne.Do(func() {
+		g.titleBusy = false
+		g.titleKey = key
+		g.titleChances = chances
+		if !g.livePlaying.Load() {
+			g.refreshDisplay()
+		}
+	})
 }
 
-// editMatchResult opens a dialog for editing match result
-func (g *GUI) editMatchResult(match *Match) {
-	// create entry fields for the goals
-	homeEntry := widget.NewEntry()
-	homeEntry.SetText(fmt.Sprintf("%d", match.HomeGoals))
-	homeEntry.Validator = func(s string) error {
+// goalsValidator checks a goals entry holds a number between 0 and maxGoals
+func goalsValidator(maxGoals int) func(s string) error {
+	return func(s string) error {
 		if s == "" {
 			return nil
 		}
@@ -567,32 +663,25 @@ func (g *GUI) editMatchResult(match *Match) {
 		if n, err := fmt.Sscanf(s, "%d", &val); err != nil || n != 1 {
 			return fmt.Errorf("invalid number")
 		}
-		if val > 9 {
-			return fmt.Errorf("maximum 9 goals allowed")
+		if val > maxGoals {
+			return fmt.Errorf("maximum %d goals allowed", maxGoals)
 		}
 		return nil
 	}
+}
+
+// editMatchResult opens a dialog for editing match result.
+// match is a copy, the change goes through the engine
+func (g *GUI) editMatchResult(match Match) {
+	// create entry fields for the goals
+	validateGoals := goalsValidator(g.engine.Settings().MaxGoals)
+	homeEntry := widget.NewEntry()
+	homeEntry.SetText(fmt.Sprintf("%d", match.HomeGoals))
+	homeEntry.Validator = validateGoals
 
 	awayEntry := widget.NewEntry()
 	awayEntry.SetText(fmt.Sprintf("%d", match.AwayGoals))
-	awayEntry.Validator = func(s string) error {
-		if s == "" {
-			return nil
-		}
-		for _, r := range s {
-			if r < '0' || r > '9' {
-				return fmt.Errorf("only numbers allowed")
-			}
-		}
-		val := 0
-		if n, err := fmt.Sscanf(s, "%d", &val); err != nil || n != 1 {
-			return fmt.Errorf("invalid number")
-		}
-		if val > 9 {
-			return fmt.Errorf("maximum 9 goals allowed")
-		}
-		return nil
-	}
+	awayEntry.Validator = validateGoals
 
 	content := container.NewVBox(
 		widget.NewLabel(fmt.Sprintf("Week %d: %s vs %s", match.Week, match.HomeTeam.Name, match.AwayTeam.Name)),
@@ -608,6 +697,9 @@ func (g *GUI) editMatchResult(match *Match) {
 
 	buttons := container.NewHBox(
 		widget.NewButton("Save", func() {
+			if homeEntry.Validate() != nil || awayEntry.Validate() != nil {
+				return
+			}
 			homeGoals := 0
 			if n, err := fmt.Sscanf(homeEntry.Text, "%d", &homeGoals); err != nil || n != 1 {
 				return
@@ -618,17 +710,13 @@ func (g *GUI) editMatchResult(match *Match) {
 				return
 			}
 
-			// update the match result
-			match.HomeGoals = homeGoals
-			match.AwayGoals = awayGoals
-			match.IsFixed = true
-			match.IsPlayed = true
-
-			// recalculate all stats
-			g.recalculateAllStats()
+			// the engine recalculates the stats and tells us to refresh
+			err := g.engine.EditResult(match.Week, match.HomeTeam.Name, match.AwayTeam.Name, homeGoals, awayGoals)
+			if err != nil {
+				fmt.Printf("Failed to edit result: %v\n", err)
+			}
 
 			dialog.Hide()
-			g.refreshDisplay()
 		}),
 		widget.NewButton("Cancel", func() {
 			dialog.Hide()
@@ -642,57 +730,61 @@ func (g *GUI) editMatchResult(match *Match) {
 
 // refreshDisplay updates all display elements
 func (g *GUI) refreshDisplay() {
-	// sort teams by points and goal difference
-	sort.Slice(g.league.Teams, func(i, j int) bool {
-		if g.league.Teams[i].Points != g.league.Teams[j].Points {
-			return g.league.Teams[i].Points > g.league.Teams[j].Points
-		}
-		return g.league.Teams[i].GoalDifference > g.league.Teams[j].GoalDifference
-	})
-
-	// create standings and probability tables
-	standingsLabel := widget.NewLabelWithStyle(g.generateStandingsTable(), fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
-	probLabel := widget.NewLabelWithStyle(g.generateProbabilityTable(), fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+	g.undoText, g.redoText = g.engine.HistoryDescriptions()
+	g.engine.Read(g.render)
+}
 
-	topRow := container.NewHBox(
-		standingsLabel,
-		widget.NewLabel("     "),
-		probLabel,
-	)
+// render builds the window content from the league
+func (g *GUI) render(league *League) {
+	g.currentWeek = league.lastPlayedWeek()
+	seasonOver := league.Week > len(league.Fixtures)
+	if seasonOver {
+		g.weekLabel.SetText(fmt.Sprintf("%s %s - Season Completed!", league.Name, league.Season))
+	} else {
+		label := fmt.Sprintf("%s %s - Week %d", league.Name, league.Season, g.currentWeek)
+		if date := league.weekDate(g.currentWeek); date != "" {
+			label += ", " + date
+		}
+		g.weekLabel.SetText(label)
+	}
 
-	var mainContent fyne.CanvasObject
+	g.standings.SetRows(standingsRows(league))
+	g.adjustmentsLabel.SetText(generateAdjustmentNotes(league))
+	g.checkTitleChances(league)
+	g.probabilities.SetRows(probabilityRows(league, g.titleChances))
+	g.predictorLabel.SetText(g.generatePredictorTable(league))
 
 	if g.showAllResults {
-		// show all season results when season is completed
-		allResultsLabel := widget.NewLabelWithStyle(g.generateAllResultsTable(), fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
-
-		// create scrollable container with explicit sizing
-		scrollContainer := container.NewScroll(allResultsLabel)
-		scrollContainer.SetMinSize(fyne.NewSize(1400, 500))
-		scrollContainer.Resize(fyne.NewSize(1400, 500))
-
-		mainContent = container.NewVBox(
-			topRow,
-			widget.NewLabel(""), // spacer
-			scrollContainer,
-		)
+		// every result of the season in one table
+		g.resultMatches = make(map[string]Match)
+		g.allResults.SetRows(g.allResultsRows(league))
+		g.weekView.Hide()
+		g.allResultsView.Show()
 	} else {
 		// show current week results and upcoming matches
 		var resultButtons []fyne.CanvasObject
-		if g.currentWeek > 0 && g.currentWeek <= len(g.league.Fixtures) {
-			weekMatches := g.league.Fixtures[g.currentWeek-1]
-
-			resultButtons = append(resultButtons, widget.NewLabel(fmt.Sprintf("Week %d Results:", g.currentWeek)))
+		if g.currentWeek > 0 && g.currentWeek <= len(league.Fixtures) {
+			header := fmt.Sprintf("Week %d Results:", g.currentWeek)
+			if date := league.weekDate(g.currentWeek); date != "" {
+				header = fmt.Sprintf("Week %d Results (%s):", g.currentWeek, date)
+			}
+			resultButtons = append(resultButtons, widget.NewLabel(header))
 			resultButtons = append(resultButtons, widget.NewLabel("----------------"))
 
-			for i := range weekMatches {
-				match := &g.league.Fixtures[g.currentWeek-1][i]
-				resultText := fmt.Sprintf("%s %d - %d %s",
+			for _, m := range league.weekMatches(g.currentWeek) {
+				match := *m
+				if !match.IsPlayed {
+					continue // abandoned since, it's in the rearranged list
+				}
+				resultText := fmt.Sprintf("%s %d - %d %s%s%s",
 					match.HomeTeam.Name, match.HomeGoals,
-					match.AwayGoals, match.AwayTeam.Name)
+					match.AwayGoals, match.AwayTeam.Name, match.penaltiesNote(), match.statusNote())
+				if match.Midweek {
+					resultText = "Midweek: " + resultText
+				}
 
 				btn := widget.NewButton(resultText, func() {
-					g.editMatchResult(match)
+					g.showMatchReport(match)
 				})
 				if match.IsFixed {
 					btn.Importance = widget.HighImportance
@@ -700,28 +792,25 @@ func (g *GUI) refreshDisplay() {
 				resultButtons = append(resultButtons, btn)
 			}
 		}
-
-		resultsContainer := container.NewVBox(resultButtons...)
-
-		// create upcoming matches table - show for week 0 through week 18
-		var upcomingMatchesLabel *widget.Label
-		if g.league.Week <= 18 {
-			upcomingMatchesLabel = widget.NewLabelWithStyle(g.generateUpcomingMatchesTable(), fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
-		} else {
-			upcomingMatchesLabel = widget.NewLabel("")
+		g.weekResults.Objects = resultButtons
+		g.weekResults.Refresh()
+
+		// upcoming matches and team news until the season is over
+		g.upcomingLabel.SetText("")
+		g.teamNewsLabel.SetText("")
+		if !seasonOver {
+			g.checkLeverage(league)
+			g.upcomingLabel.SetText(g.generateUpcomingMatchesTable(league))
+			g.teamNewsLabel.SetText(g.generateAvailabilityReport(league))
 		}
+		g.scorersLabel.SetText(g.generateScorersTable(league))
 
-		mainContent = container.NewVBox(
-			topRow,
-			widget.NewLabel(""),
-			resultsContainer,
-			widget.NewLabel(""),
-			upcomingMatchesLabel,
-		)
+		g.allResultsView.Hide()
+		g.weekView.Show()
 	}
 
 	var bottomContent fyne.CanvasObject
-	if g.league.Week > 18 || g.showAllResults {
+	if seasonOver || g.showAllResults {
 		if g.showAllResults {
 			// show back to final week button when viewing all results
 			backButton := widget.NewButton("Back to Final Week", func() {
@@ -736,72 +825,169 @@ func (g *GUI) refreshDisplay() {
 				g.showAllResults = true
 				g.refreshDisplay()
 			})
+			newSeasonButton := widget.NewButton("New Season", func() {
+				g.stopAutoPlay()
+				g.engine.NextSeason()
+			})
+			newLeagueButton := widget.NewButton("New League", g.showNewLeague)
 			championLabel := widget.NewLabelWithStyle("🏆 Season Completed! 🏆", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
-			bottomContent = container.NewVBox(championLabel, viewAllButton)
+			trendsButton := widget.NewButton("Trends", g.showTrends)
+			positionsButton := widget.NewButton("Position Chart", g.showBumpChart)
+			headToHeadButton := widget.NewButton("Head to Head", g.showHeadToHead)
+			calendarButton := widget.NewButton("Calendar", g.showCalendar)
+			bottomContent = container.NewVBox(championLabel, container.NewHBox(viewAllButton, newSeasonButton, newLeagueButton, trendsButton, positionsButton, headToHeadButton, calendarButton, widget.NewLabel("  "), g.undoButtons()))
 		}
+	} else if running, _, _ := g.autoPlay.status(); running {
+		// play all is going, just show its controls
+		bottomContent = g.autoPlayControls(g.refreshDisplay)
 	} else {
 		// button layout for simulation
 		simulateButton := widget.NewButton("Simulate Next Week", g.simulateNextWeek)
 		playAllButton := widget.NewButton("Play All Remaining Weeks", g.simulateAllRemainingWeeks)
+		predictButton := widget.NewButton("Enter Predictions", g.enterPredictions)
+		whatIfButton := widget.NewButton("What If...", g.showWhatIf)
+		scenariosButton := widget.NewButton("Scenarios", g.showScenarios)
+		needsButton := widget.NewButton("What Do We Need?", g.showRequirements)
+		trendsButton := widget.NewButton("Trends", g.showTrends)
+		headToHeadButton := widget.NewButton("Head to Head", g.showHeadToHead)
+		rearrangeButton := widget.NewButton("Rearrange", func() { g.showRearrange("") })
+		calendarButton := widget.NewButton("Calendar", g.showCalendar)
+		adjustmentsButton := widget.NewButton("Points Adjustments", g.showAdjustments)
+		settingsButton := widget.NewButton("Settings", g.showSettings)
+		newLeagueButton := widget.NewButton("New League", g.showNewLeague)
+		liveCheck := widget.NewCheck("Live mode", func(on bool) {
+			g.liveMode.Store(on)
+		})
+		liveCheck.SetChecked(g.liveMode.Load())
+
+		// pick any week from the upcoming one to the end of the season
+		var weekOptions []string
+		for week := league.upcomingWeek(); week <= len(league.Fixtures); week++ {
+			option := fmt.Sprintf("Week %d", week)
+			if date := league.weekDate(week); date != "" {
+				option += " - " + date
+			}
+			weekOptions = append(weekOptions, option)
+		}
+		weekSelect := widget.NewSelect(weekOptions, nil)
+		weekSelect.PlaceHolder = "Week..."
+		simulateToButton := widget.NewButton("Simulate To", func() {
+			var week int
+			if _, err := fmt.Sscanf(weekSelect.Selected, "Week %d", &week); err != nil {
+				return
+			}
+			g.simulateToWeek(week)
+		})
+
 		buttonRow := container.NewHBox(
 			simulateButton,
 			widget.NewLabel("  "), // spacer
 			playAllButton,
+			widget.NewLabel("  "),
+			simulateToButton,
+			weekSelect,
+			widget.NewLabel("  "),
+			predictButton,
+			whatIfButton,
+			scenariosButton,
+			needsButton,
+			trendsButton,
+			headToHeadButton,
+			calendarButton,
+			rearrangeButton,
+			adjustmentsButton,
+			settingsButton,
+			newLeagueButton,
+			widget.NewLabel("  "),
+			liveCheck,
 		)
-		bottomContent = buttonRow
+		bottomContent = container.NewVBox(buttonRow, g.undoButtons())
 	}
 
-	g.window.SetContent(container.NewVBox(
-		g.weekLabel,
-		mainContent,
-		widget.NewLabel(""),
-		bottomContent,
-	))
+	g.controls.Objects = []fyne.CanvasObject{bottomContent}
+	g.controls.Refresh()
+
+	// a live week swaps in its own view, put ours back
+	if g.window.Content() != g.content {
+		g.window.SetContent(g.content)
+	}
 
 	// adjust window size based on what we're showing
+	size := fyne.NewSize(1300, 760)
 	if g.showAllResults {
-		g.window.Resize(fyne.NewSize(1500, 900)) // extra wide for all results
-	} else {
-		g.window.Resize(fyne.NewSize(900, 700)) // normal size
+		size = fyne.NewSize(1300, 900) // taller for all results
+	}
+	if size != g.windowSize {
+		g.windowSize = size
+		g.window.Resize(size)
 	}
-
-	g.window.Canvas().Refresh(g.window.Content())
 }
 
 // generateUpcomingMatchesTable creates a table showing next week's matches
-func (g *GUI) generateUpcomingMatchesTable() string {
-	// special case for week 0 to show week 1 matches
-	if g.league.Week == 0 {
-		if g.league.Fixtures == nil {
-			g.league.Fixtures = g.league.generateFixtures()
-		}
-		var sb strings.Builder
-		sb.WriteString("Upcoming Matches (Week 1)\n")
-		sb.WriteString("----------------------------------------\n")
-
-		for _, match := range g.league.Fixtures[0] {
-			sb.WriteString(fmt.Sprintf("%-20s vs %-20s\n", match.HomeTeam.Name, match.AwayTeam.Name))
-		}
-		return sb.String()
-	}
-
-	currentWeek := g.league.Week - 1 // adjust for the actual current week
-	if currentWeek >= len(g.league.Fixtures) || currentWeek >= 18 {
+func (g *GUI) generateUpcomingMatchesTable(league *League) string {
+	week := league.upcomingWeek()
+	if week > len(league.Fixtures) {
 		return ""
 	}
 
 	var sb strings.Builder
-	sb.WriteString("Upcoming Matches (Week " + fmt.Sprintf("%d", currentWeek+1) + ")\n")
+	if date := league.weekDate(week); date != "" {
+		sb.WriteString(fmt.Sprintf("Upcoming Matches (Week %d, %s)\n", week, date))
+	} else {
+		sb.WriteString(fmt.Sprintf("Upcoming Matches (Week %d)\n", week))
+	}
 	sb.WriteString("----------------------------------------\n")
 
-	nextWeekMatches := g.league.Fixtures[currentWeek]
-	for _, match := range nextWeekMatches {
-		sb.WriteString(fmt.Sprintf("%-20s vs %-20s\n", match.HomeTeam.Name, match.AwayTeam.Name))
+	for _, match := range league.weekMatches(week) {
+		when := ""
+		if match.Midweek {
+			when = "midweek "
+		}
+		if kickoff := match.kickoffText(); kickoff != "" {
+			when = kickoff + "  "
+		}
+		if match.Status == MatchWalkover {
+			sb.WriteString(fmt.Sprintf("%s%-20s %d - %d %-20s (walkover)\n", when, match.HomeTeam.Name, match.HomeGoals, match.AwayGoals, match.AwayTeam.Name))
+			continue
+		}
+		if match.isPinned() {
+			sb.WriteString(fmt.Sprintf("%s%-20s %d - %d %-20s (pinned)\n", when, match.HomeTeam.Name, match.HomeGoals, match.AwayGoals, match.AwayTeam.Name))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%s%-20s vs %-20s", when, match.HomeTeam.Name, match.AwayTeam.Name))
+		if match.Week != week {
+			sb.WriteString(fmt.Sprintf(" (from week %d)", match.Week))
+		}
+
+		// how much the result matters, once it's been worked out
+		if leverage, ok := g.leverage[fixtureKey(match.Week, match.HomeTeam.Name, match.AwayTeam.Name)]; ok {
+			sb.WriteString(fmt.Sprintf(" swing %4.1f%% (%s)", leverage.Swing, getShortName(leverage.MostAffected)))
+		}
+		sb.WriteString("\n")
 	}
 
 	return sb.String()
 }
 
+// the week whose fixtures are shown as upcoming
+func (l *League) upcomingWeek() int {
+	if l.Week == 0 {
+		return 1
+	}
+	return l.Week
+}
+
+// the last week that has been played, 0 before kick off
+func (l *League) lastPlayedWeek() int {
+	if l.Week == 0 {
+		return 0
+	}
+	if l.Week-1 > len(l.Fixtures) {
+		return len(l.Fixtures)
+	}
+	return l.Week - 1
+}
+
 // monte carlo simulation for championship probability - this is the fun part
 func (l *League) ChampionshipProbabilities(simulations int) map[string]float64 {
 	counts := make(map[string]float64)
@@ -811,29 +997,11 @@ func (l *League) ChampionshipProbabilities(simulations int) map[string]float64 {
 	}
 
 	// if season is over, just figure out who won
-	if l.Week > 18 {
-		maxPoints := -1
-		maxGoalDiff := -999
-		for _, t := range l.Teams {
-			if t.Points > maxPoints {
-				maxPoints = t.Points
-			}
-		}
-		// find team(s) with best goal difference among those with max points
-		var champions []string
-		for _, t := range l.Teams {
-			if t.Points == maxPoints {
-				if t.GoalDifference > maxGoalDiff {
-					maxGoalDiff = t.GoalDifference
-					champions = []string{t.Name} // new leader
-				} else if t.GoalDifference == maxGoalDiff {
-					champions = append(champions, t.Name)
-				}
-			}
-		}
+	if l.Week > len(l.Fixtures) {
+		winners := champions(l.Teams)
 		// give them the probabilities
-		for _, name := range champions {
-			counts[name] = 100.0 / float64(len(champions))
+		for _, name := range winners {
+			counts[name] = 100.0 / float64(len(winners))
 		}
 		// everyone else gets 0%
 		for _, t := range l.Teams {
@@ -851,8 +1019,7 @@ func (l *League) ChampionshipProbabilities(simulations int) map[string]float64 {
 	// get current points and max possible for each team
 	for _, t := range l.Teams {
 		currentPoints[t.Name] = t.Points
-		remainingGames := 18 - t.Played                     // total games is 18
-		maxPoints[t.Name] = t.Points + (remainingGames * 3) // max points from remaining
+		maxPoints[t.Name] = t.Points + l.gamesLeft(t)*settings.Points.maxPerMatch() // max points from remaining
 	}
 
 	// check if leader has already won
@@ -877,8 +1044,8 @@ func (l *League) ChampionshipProbabilities(simulations int) map[string]float64 {
 		return counts
 	}
 
-	// if it's the start, base it on team strengths
-	if l.Week == 0 {
+	// if it's the start, base it on team strengths - unless results have been pinned or awarded
+	if l.Week == 0 && !l.hasPins() && len(l.unplayedMatches()) == l.fixtureCount() {
 		totalStrength := 0
 		for _, t := range l.Teams {
 			totalStrength += t.BaseStrength
@@ -890,72 +1057,18 @@ func (l *League) ChampionshipProbabilities(simulations int) map[string]float64 {
 	}
 
 	// otherwise run the monte carlo simulation
+	r := newSimulationRand()
 	validSimulations := 0
 	for sim := 0; sim < simulations; sim++ {
-		// make copies of all teams
-		teamsCopy := make([]*Team, numTeams)
-		for i, t := range l.Teams {
-			formCopy := make([]string, len(t.Form))
-			copy(formCopy, t.Form)
-			teamsCopy[i] = &Team{
-				Name:            t.Name,
-				Played:          t.Played,
-				Won:             t.Won,
-				Drawn:           t.Drawn,
-				Lost:            t.Lost,
-				GoalsFor:        t.GoalsFor,
-				GoalsAgainst:    t.GoalsAgainst,
-				GoalDifference:  t.GoalDifference,
-				Points:          t.Points,
-				BaseStrength:    t.BaseStrength,
-				CurrentStrength: t.CurrentStrength,
-				Form:            formCopy,
-			}
-		}
-		// simulate the rest of the season
-		for w := l.Week - 1; w < len(l.Fixtures); w++ {
-			for _, match := range l.Fixtures[w] {
-				var home, away *Team
-				for _, t := range teamsCopy {
-					if t.Name == match.HomeTeam.Name {
-						home = t
-					}
-					if t.Name == match.AwayTeam.Name {
-						away = t
-					}
-				}
-				if home == nil || away == nil {
-					continue // skip bad matches
-				}
-				hg, ag := predictMatchResult(home, away)
-				home.UpdateTeamStats(hg, ag)
-				away.UpdateTeamStats(ag, hg)
-			}
-		}
-		// find who won based on points and goal difference
-		maxPoints := -1
-		maxGoalDiff := -999
-		for _, t := range teamsCopy {
-			if t.Points > maxPoints {
-				maxPoints = t.Points
-			}
-		}
-		var champions []string
-		for _, t := range teamsCopy {
-			if t.Points == maxPoints {
-				if t.GoalDifference > maxGoalDiff {
-					maxGoalDiff = t.GoalDifference
-					champions = []string{t.Name}
-				} else if t.GoalDifference == maxGoalDiff {
-					champions = append(champions, t.Name)
-				}
-			}
-		}
-		if len(champions) == 0 {
+		teamsCopy := l.copyTeams()
+		l.simulateRemaining(teamsCopy, nil, r)
+		// find who won based on points and the tie breakers
+		winners := champions(teamsCopy)
+		if len(winners) == 0 {
 			continue // skip if something went wrong
 		}
-		for _, name := range champions {
-			counts[name] += 1.0 / float64(len(champions))
+		for _, name := range winners {
+			counts[name] += 1.0 / float64(len(winners))
 		}
 		validSimulations++
 	}
@@ -973,111 +1086,206 @@ func (l *League) ChampionshipProbabilities(simulations int) map[string]float64 {
 	return counts
 }
 
-// ResetTeamStats resets all team statistics to zero
-func (t *Team) ResetTeamStats() {
-	t.Played = 0
-	t.Won = 0
-	t.Drawn = 0
-	t.Lost = 0
-	t.GoalsFor = 0
-	t.GoalsAgainst = 0
-	t.GoalDifference = 0
-	t.Points = 0
+// the teams top of the table, more than one if they can't be separated
+func champions(teams []*Team) []string {
+	table := append([]*Team(nil), teams...)
+	sortTeams(table)
+
+	var names []string
+	for _, t := range table {
+		if len(names) > 0 && compareTeams(table[0], t) != 0 {
+			break
+		}
+		names = append(names, t.Name)
+	}
+	return names
 }
 
-// simulate all remaining weeks until the season ends automatically
-func (g *GUI) simulateAllRemainingWeeks() {
-	if g.league.Week == 0 {
-		g.league.Week = 1
-		g.league.Fixtures = g.league.generateFixtures()
-		// set week numbers for all matches
-		for week := range g.league.Fixtures {
-			for i := range g.league.Fixtures[week] {
-				g.league.Fixtures[week][i].Week = week + 1
+// fixtures a team still has to play
+func (l *League) gamesLeft(team *Team) int {
+	games := 0
+	for week := range l.Fixtures {
+		for _, match := range l.Fixtures[week] {
+			if !match.IsPlayed && (match.HomeTeam == team || match.AwayTeam == team) {
+				games++
 			}
 		}
 	}
+	return games
+}
 
-	// use a timer to go week by week without freezing the ui
-	g.simulateWeekByWeek()
+// make copies of all teams so a simulated season doesn't touch the real ones
+func (l *League) copyTeams() []*Team {
+	teamsCopy := make([]*Team, len(l.Teams))
+	for i, t := range l.Teams {
+		formCopy := make([]string, len(t.Form))
+		copy(formCopy, t.Form)
+		teamsCopy[i] = &Team{
+			Name:            t.Name,
+			Played:          t.Played,
+			Won:             t.Won,
+			Drawn:           t.Drawn,
+			Lost:            t.Lost,
+			GoalsFor:        t.GoalsFor,
+			GoalsAgainst:    t.GoalsAgainst,
+			GoalDifference:  t.GoalDifference,
+			Points:          t.Points,
+			Adjustment:      t.Adjustment,
+			BaseStrength:    t.BaseStrength,
+			CurrentStrength: t.CurrentStrength,
+			Availability:    t.Availability,
+			Form:            formCopy,
+			Squad:           t.Squad, // only read, to see who's missing
+		}
+	}
+	return teamsCopy
 }
 
-// simulate one week at a time using timers so we can see the progression
-func (g *GUI) simulateWeekByWeek() {
-	if g.league.Week > 18 {
-		// season is done - update on main thread but don't show all results yet
-		fyne.Do(func() {
-			g.currentWeek = 18
-			g.weekLabel.SetText("Season Completed!")
-			// keep showAllResults false so user sees final week first
-			g.refreshDisplay()
-		})
-		return
+// a copy of the league to work things out on away from the engine's lock: the teams and
+// their squads, the fixtures pointing at the copied teams, the dates and the adjustments.
+// history and match timelines are left out
+func (l *League) snapshot() *League {
+	copied := &League{
+		ID:          l.ID,
+		Revision:    l.Revision,
+		Name:        l.Name,
+		Season:      l.Season,
+		Seed:        l.Seed,
+		Week:        l.Week,
+		Rounds:      append([]Round(nil), l.Rounds...),
+		Calendar:    l.Calendar,
+		Adjustments: append([]PointsAdjustment(nil), l.Adjustments...),
 	}
+	teams := make(map[*Team]*Team)
+	for _, t := range l.Teams {
+		team := *t
+		team.Form = append([]string(nil), t.Form...)
+		team.Squad = make([]*Player, len(t.Squad))
+		for i, p := range t.Squad {
+			player := *p
+			team.Squad[i] = &player
+		}
+		teams[t] = &team
+		copied.Teams = append(copied.Teams, &team)
+	}
+	copied.Fixtures = make([][]Match, len(l.Fixtures))
+	for w := range l.Fixtures {
+		copied.Fixtures[w] = make([]Match, len(l.Fixtures[w]))
+		for i, match := range l.Fixtures[w] {
+			match.HomeTeam, match.AwayTeam = teams[match.HomeTeam], teams[match.AwayTeam]
+			match.Events, match.Goals, match.Cards, match.Injuries = nil, nil, nil, nil
+			copied.Fixtures[w][i] = match
+		}
+	}
+	return copied
+}
 
-	// play the current week's matches
-	if g.league.Week-1 < len(g.league.Fixtures) {
-		weekMatches := g.league.Fixtures[g.league.Week-1]
-		for i := range weekMatches {
-			match := &g.league.Fixtures[g.league.Week-1][i]
-			if !match.IsFixed {
-				homeGoals, awayGoals := predictMatchResult(match.HomeTeam, match.AwayTeam)
-				match.HomeGoals = homeGoals
-				match.AwayGoals = awayGoals
-				match.IsPlayed = true
+// simulate the rest of the season on copied teams. pinned results are used as they are,
+// and pins (by fixtureKey) can add more or override the league's own
+func (l *League) simulateRemaining(teamsCopy []*Team, pins map[string]ScenarioPin, r *rand.Rand) {
+	// rearranged matches are played in the week they've moved to, so with the form the teams have then
+	for week := l.upcomingWeek(); week <= len(l.Fixtures); week++ {
+		for _, match := range l.weekMatches(week) {
+			if match.IsPlayed {
+				continue // a walkover, already counted
+			}
+			var home, away *Team
+			for _, t := range teamsCopy {
+				if t.Name == match.HomeTeam.Name {
+					home = t
+				}
+				if t.Name == match.AwayTeam.Name {
+					away = t
+				}
+			}
+			if home == nil || away == nil {
+				continue // skip bad matches
+			}
+			// injured and banned players come back as the weeks go by
+			for _, t := range []*Team{home, away} {
+				t.Availability = t.availabilityFactor(week)
+				t.updateTeamStrength()
+			}
+			hg, ag := match.HomeGoals, match.AwayGoals // pinned, what if
+			if pin, ok := pins[fixtureKey(match.Week, match.HomeTeam.Name, match.AwayTeam.Name)]; ok {
+				hg, ag = pin.HomeGoals, pin.AwayGoals
+			} else if !match.isPinned() {
+				hg, ag = predictMatchResult(home, away, r)
 			}
+			// a pinned score is only the score, the shootout is still to be had
+			var hp, ap int
+			if hg == ag && settings.Points.Shootouts {
+				hp, ap = penaltyShootout(r)
+			}
+			home.UpdateTeamStats(hg, ag, hp, ap)
+			away.UpdateTeamStats(ag, hg, ap, hp)
 		}
 	}
+}
 
-	g.currentWeek = g.league.Week
-	g.league.Week++
-
-	// recalculate all stats after each week
-	g.recalculateAllStats()
+// ResetTeamStats resets all team statistics to zero
+func (t *Team) ResetTeamStats() {
+	t.Played = 0
+	t.Won = 0
+	t.Drawn = 0
+	t.Lost = 0
+	t.GoalsFor = 0
+	t.GoalsAgainst = 0
+	t.GoalDifference = 0
+	t.Points = 0
+	t.Adjustment = 0
+	t.Form = make([]string, 5)
+}
 
-	// update display on main thread
-	fyne.Do(func() {
-		g.weekLabel.SetText(fmt.Sprintf("Week %d", g.currentWeek))
-		g.refreshDisplay()
+// simulate all remaining weeks until the season ends automatically
+func (g *GUI) simulateAllRemainingWeeks() {
+	var lastWeek int
+	g.engine.Read(func(league *League) {
+		lastWeek = len(league.Fixtures)
 	})
 
-	// schedule the next week after a delay (500ms)
-	time.AfterFunc(500*time.Millisecond, func() {
-		g.simulateWeekByWeek()
-	})
+	// use a timer to go week by week without freezing the ui
+	g.simulateToWeek(lastWeek)
 }
 
-// generate a big table with all match results by week
-func (g *GUI) generateAllResultsTable() string {
-	if !g.showAllResults || len(g.league.Fixtures) == 0 {
-		return ""
-	}
-
-	var results strings.Builder
-	results.WriteString("ALL SEASON RESULTS\n")
-	results.WriteString("==================================================\n\n")
-
-	for week := 0; week < len(g.league.Fixtures) && week < 18; week++ {
-		results.WriteString(fmt.Sprintf("Week %d:\n", week+1))
-		results.WriteString("--------------------------------------------------\n")
-
-		for _, match := range g.league.Fixtures[week] {
-			if match.IsPlayed || match.IsFixed {
-				// use max width for team names with clear spacing
-				homeTeam := fmt.Sprintf("%-35s", match.HomeTeam.Name)
-				awayTeam := fmt.Sprintf("%-35s", match.AwayTeam.Name)
-				score := fmt.Sprintf("%d - %d", match.HomeGoals, match.AwayGoals)
-
-				results.WriteString(fmt.Sprintf("%s  %s  %s", homeTeam, score, awayTeam))
+// every result of the season, week by week. the matches are kept so picking one can show its report
+func (g *GUI) allResultsRows(league *League) []tableRow {
+	var rows []tableRow
+	for week := 1; week <= len(league.Fixtures); week++ {
+		for _, m := range league.weekMatches(week) {
+			match := *m
+			if !match.IsPlayed {
+				continue
+			}
+			key := fixtureKey(match.Week, match.HomeTeam.Name, match.AwayTeam.Name)
+			g.resultMatches[key] = match
 
-				if match.IsFixed {
-					results.WriteString("  (FIXED)")
-				}
-				results.WriteString("\n")
+			note := ""
+			if match.HomePenalties != match.AwayPenalties {
+				note = fmt.Sprintf("%d-%d pens ", match.HomePenalties, match.AwayPenalties)
+			}
+			if match.Status != "" {
+				note += strings.TrimSpace(match.statusNote()) + " "
 			}
+			if match.IsFixed && match.Status != MatchWalkover {
+				note += "(FIXED)"
+			}
+			kickoff := "" // sorts by date as it is
+			if !match.Kickoff.IsZero() {
+				kickoff = match.Kickoff.Format(kickoffFormat)
+			}
+			rows = append(rows, tableRow{
+				Key: key,
+				Cells: []string{
+					fmt.Sprintf("%d", match.dueWeek()),
+					kickoff,
+					match.HomeTeam.Name,
+					fmt.Sprintf("%d - %d", match.HomeGoals, match.AwayGoals),
+					match.AwayTeam.Name,
+					note,
+				},
+			})
 		}
-		results.WriteString("\n")
 	}
-
-	return results.String()
+	return rows
 }
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"image/color"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// a column in a sortableTable
+type tableColumn struct {
+	Title string
+	Width float32
+}
+
+// a row in a sortableTable
+type tableRow struct {
+	Key   string // identifies the row, so the selection survives an update
+	Cells []string
+}
+
+// sortableTable is a table whose columns sort when their header is clicked.
+// cells holding a team name get the team's colour badge
+type sortableTable struct {
+	table      *widget.Table
+	holder     *fyne.Container // sizes the table to fit its rows
+	columns    []tableColumn
+	given      []tableRow // in the order they were set
+	rows       []tableRow // as shown
+	sortColumn int        // -1 leaves the rows in the order given
+	descending bool
+	selected   string // key of the selected row
+	restoring  bool   // putting the selection back after an update, not the user picking a row
+	maxRows    int    // rows to make room for before it scrolls
+	width      float32
+	rowHeight  float32
+	OnSelected func(row tableRow)
+}
+
+// create a table with the given columns, tall enough for maxRows rows
+func newSortableTable(columns []tableColumn, maxRows int) *sortableTable {
+	t := &sortableTable{columns: columns, sortColumn: -1, maxRows: maxRows}
+	t.table = widget.NewTable(
+		func() (int, int) { return len(t.rows), len(t.columns) },
+		func() fyne.CanvasObject {
+			badge := canvas.NewRectangle(color.Transparent)
+			badge.SetMinSize(fyne.NewSize(10, 10))
+			return container.NewHBox(container.NewCenter(badge), widget.NewLabel(""))
+		},
+		t.updateCell,
+	)
+
+	t.table.ShowHeaderRow = true
+	t.table.CreateHeader = func() fyne.CanvasObject {
+		header := widget.NewButton("", nil)
+		header.Importance = widget.LowImportance
+		header.Alignment = widget.ButtonAlignLeading
+		return header
+	}
+	t.table.UpdateHeader = func(id widget.TableCellID, o fyne.CanvasObject) {
+		if id.Col < 0 || id.Col >= len(t.columns) {
+			return
+		}
+		header := o.(*widget.Button)
+		title := t.columns[id.Col].Title
+		if id.Col == t.sortColumn {
+			if t.descending {
+				title += " ▼"
+			} else {
+				title += " ▲"
+			}
+		}
+		header.SetText(title)
+		header.OnTapped = func() { t.sortBy(id.Col) }
+	}
+
+	t.table.OnSelected = func(id widget.TableCellID) {
+		if id.Row < 0 || id.Row >= len(t.rows) {
+			return
+		}
+		t.selected = t.rows[id.Row].Key
+		if !t.restoring && t.OnSelected != nil {
+			t.OnSelected(t.rows[id.Row])
+		}
+	}
+
+	for i, column := range columns {
+		t.table.SetColumnWidth(i, column.Width)
+		t.width += column.Width
+	}
+	t.rowHeight = t.table.CreateCell().MinSize().Height
+	t.holder = container.New(layout.NewGridWrapLayout(fyne.NewSize(t.width, t.rowHeight)), t.table)
+	return t
+}
+
+// fill in one cell
+func (t *sortableTable) updateCell(id widget.TableCellID, o fyne.CanvasObject) {
+	if id.Row < 0 || id.Row >= len(t.rows) || id.Col >= len(t.rows[id.Row].Cells) {
+		return
+	}
+	text := t.rows[id.Row].Cells[id.Col]
+	cell := o.(*fyne.Container)
+	badge := cell.Objects[0].(*fyne.Container).Objects[0].(*canvas.Rectangle)
+	label := cell.Objects[1].(*widget.Label)
+
+	label.SetText(text)
+	name := text
+	if key := t.rows[id.Row].Key; key != "" && strings.HasPrefix(text, key+" (") {
+		name = key // a team with a note after it, like a points adjustment
+	}
+	if c, ok := teamColor(name); ok {
+		badge.FillColor = c
+		badge.Show()
+	} else {
+		badge.Hide()
+	}
+	badge.Refresh()
+}
+
+// SetRows replaces what's in the table, keeping the sort order and selection
+func (t *sortableTable) SetRows(rows []tableRow) {
+	t.given = rows
+	t.apply()
+
+	// room for the header and the rows, up to maxRows
+	shown := len(rows)
+	if shown > t.maxRows {
+		shown = t.maxRows
+	}
+	t.holder.Layout = layout.NewGridWrapLayout(fyne.NewSize(t.width, float32(shown+1)*(t.rowHeight+1)))
+	t.holder.Refresh()
+}
+
+// ClearSelection unselects the selected row
+func (t *sortableTable) ClearSelection() {
+	t.selected = ""
+	t.table.UnselectAll()
+}
+
+// sort by a column. clicking the same header again flips the order, a third time goes back to how it came
+func (t *sortableTable) sortBy(col int) {
+	switch {
+	case col != t.sortColumn:
+		t.sortColumn, t.descending = col, false
+	case !t.descending:
+		t.descending = true
+	default:
+		t.sortColumn = -1
+	}
+	t.apply()
+}
+
+// put the rows in order and the selection back where it was
+func (t *sortableTable) apply() {
+	t.rows = append([]tableRow(nil), t.given...)
+	if col := t.sortColumn; col >= 0 {
+		sort.SliceStable(t.rows, func(i, j int) bool {
+			if t.descending {
+				return cellLess(t.rows[j].Cells[col], t.rows[i].Cells[col])
+			}
+			return cellLess(t.rows[i].Cells[col], t.rows[j].Cells[col])
+		})
+	}
+	t.table.Refresh()
+
+	t.restoring = true
+	t.table.UnselectAll()
+	for i, row := range t.rows {
+		if row.Key != "" && row.Key == t.selected {
+			t.table.Select(widget.TableCellID{Row: i, Col: 0})
+			break
+		}
+	}
+	t.restoring = false
+}
+
+// compare two cells, as numbers if they both are one
+func cellLess(a, b string) bool {
+	x, errA := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(a), "%"), 64)
+	y, errB := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(b), "%"), 64)
+	if errA == nil && errB == nil {
+		return x < y
+	}
+	return strings.ToLower(a) < strings.ToLower(b)
+}
+
+// teamColor is the club colour for a team's badge, false if name isn't a team
+func teamColor(name string) (color.NRGBA, bool) {
+	if team, ok := leagueConfig.team(name); ok && team.Color != "" {
+		if c, err := parseColor(team.Color); err == nil {
+			return c, true
+		}
+	}
+	for _, team := range getMockPremierLeagueTeams() {
+		if team.Name == name {
+			return team.Color, true
+		}
+	}
+	return color.NRGBA{}, false
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// simulations for a team's finishing position chances on its detail page
+const teamDetailSimulations = 2000
+
+// a team's record at home or away
+type venueRecord struct {
+	Played       int
+	Won          int
+	Drawn        int
+	Lost         int
+	GoalsFor     int
+	GoalsAgainst int
+	Points       int
+}
+
+// add a result and the points it was worth to the record
+func (r *venueRecord) add(goalsFor, goalsAgainst, points int) {
+	r.Played++
+	r.GoalsFor += goalsFor
+	r.GoalsAgainst += goalsAgainst
+	r.Points += points
+	switch {
+	case goalsFor > goalsAgainst:
+		r.Won++
+	case goalsFor == goalsAgainst:
+		r.Drawn++
+	default:
+		r.Lost++
+	}
+}
+
+// every fixture a team is in, in the order they're played
+func (l *League) teamMatches(name string) []*Match {
+	var matches []*Match
+	for week := 1; week <= len(l.Fixtures); week++ {
+		for _, match := range l.weekMatches(week) {
+			if match.HomeTeam.Name == name || match.AwayTeam.Name == name {
+				matches = append(matches, match)
+			}
+		}
+	}
+	return matches
+}
+
+// the team's goals, the opponent's goals and the opponent, from the team's side of a match
+func (m *Match) from(name string) (int, int, *Team) {
+	if m.HomeTeam.Name == name {
+		return m.HomeGoals, m.AwayGoals, m.AwayTeam
+	}
+	return m.AwayGoals, m.HomeGoals, m.HomeTeam
+}
+
+// the points a played match was worth to the named team
+func (m *Match) pointsFor(name string) int {
+	if m.HomeTeam.Name == name {
+		return settings.Points.forResult(m.HomeGoals, m.AwayGoals, m.HomePenalties, m.AwayPenalties)
+	}
+	return settings.Points.forResult(m.AwayGoals, m.HomeGoals, m.AwayPenalties, m.HomePenalties)
+}
+
+// W, D or L for a score
+func resultLetter(goalsFor, goalsAgainst int) string {
+	switch {
+	case goalsFor > goalsAgainst:
+		return "W"
+	case goalsFor == goalsAgainst:
+		return "D"
+	}
+	return "L"
+}
+
+// homeAwaySplits is the team's record at home and away
+func (l *League) homeAwaySplits(name string) (venueRecord, venueRecord) {
+	var home, away venueRecord
+	for _, match := range l.teamMatches(name) {
+		if !match.IsPlayed {
+			continue
+		}
+		goalsFor, goalsAgainst, _ := match.from(name)
+		if match.HomeTeam.Name == name {
+			home.add(goalsFor, goalsAgainst, match.pointsFor(name))
+		} else {
+			away.add(goalsFor, goalsAgainst, match.pointsFor(name))
+		}
+	}
+	return home, away
+}
+
+// formHistory is every result of the season so far, oldest first
+func (l *League) formHistory(name string) []string {
+	var form []string
+	for _, match := range l.teamMatches(name) {
+		if match.IsPlayed {
+			goalsFor, goalsAgainst, _ := match.from(name)
+			form = append(form, resultLetter(goalsFor, goalsAgainst))
+		}
+	}
+	return form
+}
+
+// chances of a home win, draw and away win, the same odds predictMatchResult rolls against
+func matchOutcomeProbabilities(home, away *Team) (float64, float64, float64) {
+	if settings.MatchModel == MatchModelPoisson {
+		expectedHome, expectedAway := expectedGoals(home, away)
+		homeChances, awayChances := goalChances(expectedHome), goalChances(expectedAway)
+		var homeWin, draw, awayWin float64
+		for h, ph := range homeChances {
+			for a, pa := range awayChances {
+				switch {
+				case h > a:
+					homeWin += ph * pa
+				case h == a:
+					draw += ph * pa
+				default:
+					awayWin += ph * pa
+				}
+			}
+		}
+		return homeWin, draw, awayWin
+	}
+
+	homeWin := float64(home.CurrentStrength) / float64(home.CurrentStrength+away.CurrentStrength)
+	draw := settings.DrawChance
+	if homeWin+draw > 1 {
+		draw = 1 - homeWin
+	}
+	return homeWin, draw, 1 - homeWin - draw
+}
+
+// chance of each number of goals poissonGoals can give, anything past the cap counting as the cap
+func goalChances(expected float64) []float64 {
+	chances := make([]float64, settings.MaxGoals+1)
+	rest := 1.0
+	for goals := 0; goals < settings.MaxGoals; goals++ {
+		chances[goals] = poissonChance(expected, goals)
+		rest -= chances[goals]
+	}
+	chances[settings.MaxGoals] = max(rest, 0)
+	return chances
+}
+
+// strengthChart plots the team's strength after each week, starting from its base strength
+func (l *League) strengthChart(team *Team) *lineChart {
+	// as far as form can move the strength either way
+	low := float64(team.BaseStrength) * (1 - settings.FormCap)
+	high := float64(team.BaseStrength) * (1 + settings.FormCap)
+	chart := &lineChart{
+		Title:  "Strength (from form)",
+		XLabel: "Week",
+		XMax:   len(l.Fixtures),
+		YMin:   float64(int(low/5) * 5),
+		YMax:   float64(int(high/5)*5 + 5),
+		YStep:  5,
+	}
+
+	series := chartSeries{Name: team.Name, Color: chartColors[0]}
+	if c, ok := teamColor(team.Name); ok {
+		series.Color = c
+	}
+	series.Points = append(series.Points, chartPoint{X: 0, Y: float64(team.BaseStrength)})
+	for _, standings := range l.PositionHistory {
+		series.Points = append(series.Points, chartPoint{X: standings.Week, Y: float64(standings.Strength[team.Name])})
+	}
+	chart.Series = append(chart.Series, series)
+	return chart
+}
+
+// generateTeamReport writes out a team's season: record, splits, form, results, fixtures to come
+// and where it's likely to finish
+func generateTeamReport(league *League, team *Team) string {
+	var sb strings.Builder
+
+	position := 0
+	for i, t := range league.Teams {
+		if t == team {
+			position = i + 1
+		}
+	}
+	sb.WriteString(fmt.Sprintf("%s - %s on %d points\n", team.Name, positionName(position), team.Points))
+	sb.WriteString(fmt.Sprintf("Strength %d (base %d), %.0f%% of the squad available\n\n", team.CurrentStrength, team.BaseStrength, team.Availability*100))
+
+	// home and away
+	home, away := league.homeAwaySplits(team.Name)
+	sb.WriteString(fmt.Sprintf("%-6s %3s %3s %3s %3s %3s %3s %4s\n", "", "P", "W", "D", "L", "GF", "GA", "Pts"))
+	for _, split := range []struct {
+		label  string
+		record venueRecord
+	}{{"Home", home}, {"Away", away}} {
+		r := split.record
+		sb.WriteString(fmt.Sprintf("%-6s %3d %3d %3d %3d %3d %3d %4d\n", split.label, r.Played, r.Won, r.Drawn, r.Lost, r.GoalsFor, r.GoalsAgainst, r.Points))
+	}
+
+	// points the league has taken off or given back
+	adjusted := false
+	for _, a := range league.Adjustments {
+		if a.Team == team.Name {
+			if !adjusted {
+				sb.WriteString("\nPoints adjustments\n")
+				adjusted = true
+			}
+			sb.WriteString(fmt.Sprintf("%s on %s, from week %d: %s\n", a.pointsText(), a.Date.Format(adjustmentDateFormat), a.Week, a.Reason))
+		}
+	}
+
+	form := league.formHistory(team.Name)
+	if len(form) == 0 {
+		sb.WriteString("\nForm: no games played yet\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("\nForm (oldest first): %s\n", strings.Join(form, " ")))
+	}
+
+	// results and fixtures
+	sb.WriteString("\nResults\n")
+	sb.WriteString("--------------------------------------------------\n")
+	var upcoming []*Match
+	for _, match := range league.teamMatches(team.Name) {
+		if !match.IsPlayed {
+			upcoming = append(upcoming, match)
+			continue
+		}
+		goalsFor, goalsAgainst, opponent := match.from(team.Name)
+		venue := "A"
+		if match.HomeTeam == team {
+			venue = "H"
+		}
+		note := ""
+		if match.HomePenalties != match.AwayPenalties {
+			penaltiesFor, penaltiesAgainst := match.HomePenalties, match.AwayPenalties
+			if match.AwayTeam == team {
+				penaltiesFor, penaltiesAgainst = penaltiesAgainst, penaltiesFor
+			}
+			note = fmt.Sprintf(" (%d - %d pens)", penaltiesFor, penaltiesAgainst)
+		}
+		note += match.statusNote()
+		if match.IsFixed && match.Status != MatchWalkover {
+			note += " (fixed)"
+		}
+		sb.WriteString(fmt.Sprintf("Week %-2d %-10s %s  %-20s %d - %d  %s%s\n", match.dueWeek(), match.dayText(), venue, opponent.Name, goalsFor, goalsAgainst,
+			resultLetter(goalsFor, goalsAgainst), note))
+	}
+
+	if len(upcoming) > 0 {
+		sb.WriteString("\nFixtures to come                     Win   Draw   Loss\n")
+		sb.WriteString("--------------------------------------------------\n")
+		for _, match := range upcoming {
+			_, _, opponent := match.from(team.Name)
+			venue := "A"
+			if match.HomeTeam == team {
+				venue = "H"
+			}
+			if match.isPinned() {
+				goalsFor, goalsAgainst, _ := match.from(team.Name)
+				sb.WriteString(fmt.Sprintf("Week %-2d %-10s %s  %-15s pinned %d - %d%s\n", match.dueWeek(), match.dayText(), venue, opponent.Name, goalsFor, goalsAgainst, match.statusNote()))
+				continue
+			}
+			homeWin, draw, awayWin := matchOutcomeProbabilities(match.HomeTeam, match.AwayTeam)
+			win, loss := homeWin, awayWin
+			if match.AwayTeam == team {
+				win, loss = awayWin, homeWin
+			}
+			sb.WriteString(fmt.Sprintf("Week %-2d %-10s %s  %-15s %5.0f%% %5.0f%% %5.0f%%%s\n", match.dueWeek(), match.dayText(), venue, opponent.Name, win*100, draw*100, loss*100, match.statusNote()))
+		}
+	}
+
+	// where they'll finish
+	sb.WriteString("\nFinishing position\n")
+	sb.WriteString("--------------------------------------------------\n")
+	for i, p := range league.PositionProbabilities(teamDetailSimulations, nil)[team.Name] {
+		sb.WriteString(fmt.Sprintf("%-4s %5.1f%% %s\n", positionName(i+1), p, strings.Repeat("█", int(p/2.5+0.5))))
+	}
+	return sb.String()
+}
+
+// showTeamDetail opens a team's page
+func (g *GUI) showTeamDetail(name string) {
+	var report string
+	var chart *lineChart
+	g.engine.Read(func(league *League) {
+		if team := findTeam(league.Teams, name); team != nil {
+			report = generateTeamReport(league, team)
+			chart = league.strengthChart(team)
+		}
+	})
+	if chart == nil {
+		return
+	}
+
+	content := container.NewVBox(
+		widget.NewLabelWithStyle(report, fyne.TextAlignLeading, fyne.TextStyle{Monospace: true}),
+		chart.canvasObject(chartWidth, chartHeight),
+	)
+	scroll := container.NewVScroll(content)
+	scroll.SetMinSize(fyne.NewSize(chartWidth+20, 600))
+
+	dialog := widget.NewModalPopUp(scroll, g.window.Canvas())
+	dialog.Content = container.NewVBox(scroll, widget.NewButton("Close", func() {
+		dialog.Hide()
+	}))
+	dialog.Resize(fyne.NewSize(chartWidth+60, 680))
+	dialog.Show()
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// simulations behind each week's point on the trend chart. it's worked out
+// after every change, so fewer than the probability table uses
+const trendSimulations = 2000
+
+// what the chart can plot
+const (
+	TrendTitle     = "title"
+	TrendPosition  = "position"
+	TrendStandings = "bump" // actual league position, see bumpChart
+)
+
+// WeekProbabilities is where every team stood after a week, for the trend chart
+type WeekProbabilities struct {
+	Week             int                // weeks played, 0 before a ball is kicked
+	Title            map[string]float64 // % chance of winning the league
+	ExpectedPosition map[string]float64 // average finishing position
+}
+
+// weeks with every match played, 0 before the season starts
+func (l *League) weeksPlayed() int {
+	if l.Week < 1 {
+		return 0
+	}
+	return l.Week - 1
+}
+
+// work out the title chances and expected positions as the league stands
+func (l *League) currentProbabilities(simulations int) WeekProbabilities {
+	positions := l.PositionProbabilities(simulations, nil)
+	point := WeekProbabilities{
+		Week:             l.weeksPlayed(),
+		Title:            make(map[string]float64),
+		ExpectedPosition: make(map[string]float64),
+	}
+	for name, probs := range positions {
+		point.Title[name] = probs[0]
+		expected := 0.0
+		for i, p := range probs {
+			expected += float64(i+1) * p / 100.0
+		}
+		point.ExpectedPosition[name] = expected
+	}
+	return point
+}
+
+// bring the trend up to date after a change: anything past the weeks played
+// has been undone, and the latest week is worked out again. caller must hold the lock
+func (e *SeasonEngine) recordProbabilities() {
+	l := e.league
+	point := l.currentProbabilities(trendSimulations)
+
+	var history []WeekProbabilities
+	for _, p := range l.ProbabilityHistory {
+		if p.Week < point.Week {
+			history = append(history, p)
+		}
+	}
+	l.ProbabilityHistory = append(history, point)
+
+	if db == nil || l.ID == 0 {
+		return
+	}
+	if err := db.SaveChampionshipProbabilities(l.ID, point.Week, point.Title, point.ExpectedPosition); err != nil {
+		fmt.Printf("Failed to save probabilities: %v\n", err)
+		return
+	}
+	if err := db.DeleteProbabilitiesAfter(l.ID, point.Week); err != nil {
+		fmt.Printf("Failed to save probabilities: %v\n", err)
+	}
+}
+
+// trendChart lays the probability history out as a chart of what
+func (l *League) trendChart(what string) *lineChart {
+	chart := &lineChart{
+		XLabel: "Week",
+		XMax:   len(l.Fixtures),
+	}
+	if what == TrendPosition {
+		chart.Title = "Expected Finishing Position"
+		chart.YMin, chart.YMax = 1, float64(len(l.Teams))
+		chart.YStep = 1
+		chart.Inverted = true // top of the table at the top
+	} else {
+		chart.Title = "Title Chances (%)"
+		chart.YMin, chart.YMax = 0, 100
+		chart.YStep = 25
+	}
+
+	for i, name := range l.sortedTeamNames() {
+		series := chartSeries{Name: name, Color: chartColors[i%len(chartColors)]}
+		for _, p := range l.ProbabilityHistory {
+			y := p.Title[name]
+			if what == TrendPosition {
+				y = p.ExpectedPosition[name]
+			}
+			series.Points = append(series.Points, chartPoint{X: p.Week, Y: y})
+		}
+		chart.Series = append(chart.Series, series)
+	}
+	return chart
+}
+
+// write a chart to a file, svg or png depending on the extension
+func exportChart(chart *lineChart, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".svg":
+		err = chart.writeSVG(f, chartWidth, chartHeight)
+	case ".png":
+		err = chart.writePNG(f, chartWidth, chartHeight)
+	default:
+		err = fmt.Errorf("can't export %s, use .svg or .png", path)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// chartExportRow lets the user save a chart to a file. chart gives the one currently showing
+func (g *GUI) chartExportRow(chart func() *lineChart, defaultPath string) fyne.CanvasObject {
+	statusLabel := widget.NewLabel("")
+	fileEntry := widget.NewEntry()
+	fileEntry.SetText(defaultPath)
+	exportButton := widget.NewButton("Export", func() {
+		path := strings.TrimSpace(fileEntry.Text)
+		if err := exportChart(chart(), path); err != nil {
+			statusLabel.SetText(fmt.Sprintf("Failed to export: %v", err))
+			return
+		}
+		statusLabel.SetText(fmt.Sprintf("Saved %s", path))
+	})
+
+	return container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel("Export to (.svg or .png):"), exportButton, fileEntry),
+		statusLabel,
+	)
+}
+
+// showTrends opens the chart of how every team's chances moved week by week
+func (g *GUI) showTrends() {
+	var charts map[string]*lineChart
+	g.engine.Read(func(league *League) {
+		charts = map[string]*lineChart{
+			TrendTitle:    league.trendChart(TrendTitle),
+			TrendPosition: league.trendChart(TrendPosition),
+		}
+	})
+
+	chartHolder := container.NewStack()
+	current := TrendTitle
+	showChart := func(what string) {
+		current = what
+		chartHolder.Objects = []fyne.CanvasObject{charts[what].canvasObject(chartWidth, chartHeight)}
+		chartHolder.Refresh()
+	}
+
+	options := []string{"Title chances", "Expected position"}
+	whatSelect := widget.NewRadioGroup(options, func(selected string) {
+		if selected == options[1] {
+			showChart(TrendPosition)
+		} else {
+			showChart(TrendTitle)
+		}
+	})
+	whatSelect.Horizontal = true
+	whatSelect.SetSelected(options[0])
+
+	content := container.NewVBox(
+		whatSelect,
+		chartHolder,
+		g.chartExportRow(func() *lineChart { return charts[current] }, "trend.png"),
+	)
+	dialog := widget.NewModalPopUp(content, g.window.Canvas())
+	dialog.Content = container.NewVBox(content, widget.NewButton("Close", func() {
+		dialog.Hide()
+	}))
+	dialog.Resize(fyne.NewSize(chartWidth+40, chartHeight+200))
+	dialog.Show()
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// a result set for a match that hasn't been played yet
+func (m *Match) isPinned() bool {
+	return m.IsFixed && !m.IsPlayed
+}
+
+// does any fixture still to come have a pinned result
+func (l *League) hasPins() bool {
+	for week := l.upcomingWeek(); week <= len(l.Fixtures); week++ {
+		for _, match := range l.weekMatches(week) {
+			if match.isPinned() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// showWhatIf opens a dialog for pinning results of fixtures still to come.
+// pinned results are played out as set and the probabilities take them into account
+func (g *GUI) showWhatIf() {
+	// copies of every fixture still to come
+	var firstWeek int
+	var fixtures [][]Match
+	g.engine.Read(func(league *League) {
+		firstWeek = league.upcomingWeek()
+		for week := firstWeek; week <= len(league.Fixtures); week++ {
+			var matches []Match
+			for _, match := range league.weekMatches(week) {
+				if !match.IsPlayed {
+					matches = append(matches, *match)
+				}
+			}
+			fixtures = append(fixtures, matches)
+		}
+	})
+	if len(fixtures) == 0 {
+		return
+	}
+
+	matchList := container.NewVBox()
+	content := container.NewVBox(
+		widget.NewLabel("Pin a result for any fixture still to come"),
+	)
+	dialog := widget.NewModalPopUp(content, g.window.Canvas())
+
+	// list the chosen week's fixtures, each one opens the result editor
+	showWeek := func(week int) {
+		matchList.RemoveAll()
+		for _, match := range fixtures[week-firstWeek] {
+			text := fmt.Sprintf("%s vs %s", match.HomeTeam.Name, match.AwayTeam.Name)
+			if match.isPinned() {
+				text = fmt.Sprintf("%s %d - %d %s (pinned)", match.HomeTeam.Name, match.HomeGoals, match.AwayGoals, match.AwayTeam.Name)
+			}
+			pinButton := widget.NewButton(text, func() {
+				dialog.Hide()
+				g.editMatchResult(match)
+			})
+
+			row := container.NewHBox(pinButton)
+			if match.isPinned() {
+				row.Add(widget.NewButton("Clear", func() {
+					dialog.Hide()
+					if err := g.engine.ClearOverride(match.Week, match.HomeTeam.Name, match.AwayTeam.Name); err != nil {
+						fmt.Printf("Failed to clear pinned result: %v\n", err)
+					}
+				}))
+			}
+			matchList.Add(row)
+		}
+	}
+
+	var weekOptions []string
+	for week := firstWeek; week < firstWeek+len(fixtures); week++ {
+		weekOptions = append(weekOptions, fmt.Sprintf("Week %d", week))
+	}
+	weekSelect := widget.NewSelect(weekOptions, func(selected string) {
+		var week int
+		if _, err := fmt.Sscanf(selected, "Week %d", &week); err == nil {
+			showWeek(week)
+		}
+	})
+	weekSelect.SetSelectedIndex(0)
+
+	closeButton := widget.NewButton("Close", func() {
+		dialog.Hide()
+	})
+
+	dialog.Content = container.NewVBox(content, weekSelect, matchList, closeButton)
+	dialog.Resize(fyne.NewSize(450, 300))
+	dialog.Show()
+}